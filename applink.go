@@ -0,0 +1,225 @@
+package ogp
+
+import "strconv"
+
+// AppLinkData holds the `al:*` properties of an object, which describe how
+// to open it in the native apps of each platform.
+type AppLinkData struct {
+	IOS          []AppLinkTargetData `json:"ios,omitempty"`
+	IPhone       []AppLinkTargetData `json:"iphone,omitempty"`
+	IPad         []AppLinkTargetData `json:"ipad,omitempty"`
+	Android      []AppLinkTargetData `json:"android,omitempty"`
+	WindowsPhone []AppLinkTargetData `json:"windows_phone,omitempty"`
+	Web          *AppLinkWebData     `json:"web,omitempty"`
+}
+
+// AppLinkTargetData holds the properties of an app targeted by an App Link.
+// AppStoreID only applies to iOS apps, Package and Class to Android apps and
+// AppID to Windows Phone apps.
+type AppLinkTargetData struct {
+	URL        string `json:"url,omitempty"`
+	AppStoreID string `json:"app_store_id,omitempty"`
+	Package    string `json:"package,omitempty"`
+	Class      string `json:"class,omitempty"`
+	AppID      string `json:"app_id,omitempty"`
+	AppName    string `json:"app_name,omitempty"`
+}
+
+// AppLinkWebData holds the `al:web:*` properties, telling whether the object
+// should be opened in a browser when no app can open it.
+type AppLinkWebData struct {
+	URL            string `json:"url,omitempty"`
+	ShouldFallback bool   `json:"should_fallback"`
+}
+
+// AppLinkBuilder builds the `al:*` properties of an object.
+type AppLinkBuilder struct {
+	data AppLinkData
+}
+
+// IOS adds a new `al:ios` target, the app opened on every iOS device.
+func (b *AppLinkBuilder) IOS(appStoreID, url, appName string) *AppLinkBuilder {
+	b.data.IOS = append(b.data.IOS, AppLinkTargetData{URL: url, AppStoreID: appStoreID, AppName: appName})
+	return b
+}
+
+// IPhone adds a new `al:iphone` target, which takes precedence over the
+// `al:ios` targets on iPhones.
+func (b *AppLinkBuilder) IPhone(appStoreID, url, appName string) *AppLinkBuilder {
+	b.data.IPhone = append(b.data.IPhone, AppLinkTargetData{URL: url, AppStoreID: appStoreID, AppName: appName})
+	return b
+}
+
+// IPad adds a new `al:ipad` target, which takes precedence over the `al:ios`
+// targets on iPads.
+func (b *AppLinkBuilder) IPad(appStoreID, url, appName string) *AppLinkBuilder {
+	b.data.IPad = append(b.data.IPad, AppLinkTargetData{URL: url, AppStoreID: appStoreID, AppName: appName})
+	return b
+}
+
+// Android adds a new `al:android` target. The class of the activity to start
+// is optional.
+func (b *AppLinkBuilder) Android(pkg, class, url, appName string) *AppLinkBuilder {
+	b.data.Android = append(b.data.Android, AppLinkTargetData{URL: url, Package: pkg, Class: class, AppName: appName})
+	return b
+}
+
+// WindowsPhone adds a new `al:windows_phone` target.
+func (b *AppLinkBuilder) WindowsPhone(appID, url, appName string) *AppLinkBuilder {
+	b.data.WindowsPhone = append(b.data.WindowsPhone, AppLinkTargetData{URL: url, AppID: appID, AppName: appName})
+	return b
+}
+
+// Web sets the `al:web:url` and `al:web:should_fallback` properties. The URL
+// defaults to the URL of the object.
+func (b *AppLinkBuilder) Web(url string, shouldFallback bool) *AppLinkBuilder {
+	b.data.Web = &AppLinkWebData{URL: url, ShouldFallback: shouldFallback}
+	return b
+}
+
+// Data returns the `al:*` properties. Changes made to the returned value are
+// reflected in the builder.
+func (b *AppLinkBuilder) Data() *AppLinkData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the `al:*` properties.
+func (d AppLinkData) Builder() *AppLinkBuilder {
+	return &AppLinkBuilder{data: d}
+}
+
+// appLinkPlatform is a platform of the App Links protocol along with its
+// targets.
+type appLinkPlatform struct {
+	name    string
+	targets *[]AppLinkTargetData
+}
+
+func (d *AppLinkData) platforms() []appLinkPlatform {
+	return []appLinkPlatform{
+		{"ios", &d.IOS},
+		{"iphone", &d.IPhone},
+		{"ipad", &d.IPad},
+		{"android", &d.Android},
+		{"windows_phone", &d.WindowsPhone},
+	}
+}
+
+func (d *AppLinkData) meta(mb *metaBuilder) {
+	for _, platform := range d.platforms() {
+		for i := range *platform.targets {
+			(*platform.targets)[i].meta(mb, "al:"+platform.name)
+		}
+	}
+	if d.Web != nil {
+		if d.Web.URL != "" {
+			mb.Add("al", "web:url", d.Web.URL)
+		}
+		mb.Add("al", "web:should_fallback", strconv.FormatBool(d.Web.ShouldFallback))
+	}
+}
+
+func (d *AppLinkData) validate(v *validator, path string) {
+	for _, platform := range d.platforms() {
+		ns := "al:" + platform.name
+		for i := range *platform.targets {
+			target := &(*platform.targets)[i]
+			switch platform.name {
+			case "android":
+				v.required(join(path, ns, i), ns+":package", target.Package)
+			default:
+				v.required(join(path, ns, i), ns+":url", target.URL)
+			}
+		}
+	}
+	if d.Web != nil {
+		v.url(path, "al:web:url", d.Web.URL)
+	}
+}
+
+// decode decodes a single `al:*` property and reports whether it is known.
+// Targets being arrays of structured properties, a property already set on
+// the last target of its platform starts a new target. The identifying
+// property of a target, e.g. `al:android:package`, thus starts a new target
+// once the last one is identified, whether the other properties of the
+// target, e.g. `al:android:url`, come before or after it.
+func (d *AppLinkData) decode(p Property) bool {
+	switch p.Name {
+	case "al:web:url":
+		d.web().URL = p.Content
+		return true
+	case "al:web:should_fallback":
+		d.web().ShouldFallback, _ = strconv.ParseBool(p.Content)
+		return true
+	}
+	for _, platform := range d.platforms() {
+		ns := "al:" + platform.name + ":"
+		if len(p.Name) <= len(ns) || p.Name[:len(ns)] != ns {
+			continue
+		}
+		name := p.Name[len(ns):]
+		if (&AppLinkTargetData{}).field(name) == nil {
+			return false
+		}
+		targets := platform.targets
+		var last *AppLinkTargetData
+		if len(*targets) > 0 {
+			last = &(*targets)[len(*targets)-1]
+		}
+		if last == nil || *last.field(name) != "" {
+			*targets = append(*targets, AppLinkTargetData{})
+			last = &(*targets)[len(*targets)-1]
+		}
+		*last.field(name) = p.Content
+		return true
+	}
+	return false
+}
+
+// web returns the `al:web:*` properties, which the specification makes fall
+// back to the web by default.
+func (d *AppLinkData) web() *AppLinkWebData {
+	if d.Web == nil {
+		d.Web = &AppLinkWebData{ShouldFallback: true}
+	}
+	return d.Web
+}
+
+func (d *AppLinkTargetData) meta(mb *metaBuilder, ns string) {
+	if d.URL != "" {
+		mb.Add(ns, "url", d.URL)
+	}
+	if d.AppStoreID != "" {
+		mb.Add(ns, "app_store_id", d.AppStoreID)
+	}
+	if d.Package != "" {
+		mb.Add(ns, "package", d.Package)
+	}
+	if d.Class != "" {
+		mb.Add(ns, "class", d.Class)
+	}
+	if d.AppID != "" {
+		mb.Add(ns, "app_id", d.AppID)
+	}
+	if d.AppName != "" {
+		mb.Add(ns, "app_name", d.AppName)
+	}
+}
+
+func (d *AppLinkTargetData) field(name string) *string {
+	switch name {
+	case "url":
+		return &d.URL
+	case "app_store_id":
+		return &d.AppStoreID
+	case "package":
+		return &d.Package
+	case "class":
+		return &d.Class
+	case "app_id":
+		return &d.AppID
+	case "app_name":
+		return &d.AppName
+	}
+	return nil
+}
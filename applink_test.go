@@ -0,0 +1,110 @@
+package ogp_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestAppLink(t *testing.T) {
+	website := ogp.Website().
+		Title("Example").
+		URL("http://example.com/applinks").
+		Image(ogp.Image().URL("http://example.com/logo.png")).
+		AppLink(ogp.AppLink().
+			IOS("12345", "example://applinks", "Example App").
+			Android("com.example.android", "", "example://applinks", "Example App").
+			Android("com.example.lite", "com.example.lite.MainActivity", "", "").
+			WindowsPhone("", "example://applinks", "").
+			Web("http://example.com/web", false))
+	expected := `<meta property="og:type" content="website">
+<meta property="og:title" content="Example">
+<meta property="og:url" content="http://example.com/applinks">
+<meta property="og:image" content="http://example.com/logo.png">
+<meta property="al:ios:url" content="example://applinks">
+<meta property="al:ios:app_store_id" content="12345">
+<meta property="al:ios:app_name" content="Example App">
+<meta property="al:android:url" content="example://applinks">
+<meta property="al:android:package" content="com.example.android">
+<meta property="al:android:app_name" content="Example App">
+<meta property="al:android:package" content="com.example.lite">
+<meta property="al:android:class" content="com.example.lite.MainActivity">
+<meta property="al:windows_phone:url" content="example://applinks">
+<meta property="al:web:url" content="http://example.com/web">
+<meta property="al:web:should_fallback" content="false">`
+	if result := website.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if err := website.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	object, err := ogp.Parse(strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := object.(*ogp.WebsiteBuilder).Data().AppLink; !reflect.DeepEqual(result, website.Data().AppLink) {
+		t.Errorf("unexpected app links: %+v", result)
+	}
+}
+
+func TestAppLinkValidate(t *testing.T) {
+	website := ogp.Website().
+		Title("Example").
+		URL("http://example.com/applinks").
+		Image(ogp.Image().URL("http://example.com/logo.png")).
+		AppLink(ogp.AppLink().
+			IPhone("12345", "", "Example App").
+			Android("", "", "example://applinks", "Example App").
+			Web("/web", true))
+	expected := `ogp: al:iphone[0]: al:iphone:url is required
+ogp: al:android[0]: al:android:package is required
+ogp: al:web:url must be an absolute URL`
+	if err := website.Validate(); err == nil || err.Error() != expected {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParseAppLinkFallback(t *testing.T) {
+	object, err := ogp.Parse(strings.NewReader(`<meta property="al:web:url" content="http://example.com/web">`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	web := object.(*ogp.WebsiteBuilder).Data().AppLink.Web
+	if !web.ShouldFallback {
+		t.Error("expected al:web:should_fallback to default to true")
+	}
+}
+
+func TestParseAppLinkTargets(t *testing.T) {
+	document := `<html><head>
+		<meta property="al:android:package" content="com.example.a">
+		<meta property="al:android:url" content="example-a://page">
+		<meta property="al:android:url" content="example-b://page">
+		<meta property="al:android:package" content="com.example.b">
+		<meta property="al:android:app_name" content="B">
+		<meta property="al:android:package" content="com.example.c">
+		<meta property="al:ios:app_store_id" content="1">
+		<meta property="al:ios:app_name" content="One">
+		<meta property="al:ios:app_store_id" content="2">
+		<meta property="al:ios:url" content="two://page">
+	</head></html>`
+	object, err := ogp.Parse(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	appLink := object.(*ogp.WebsiteBuilder).Data().AppLink
+	android := []ogp.AppLinkTargetData{
+		{Package: "com.example.a", URL: "example-a://page"},
+		{URL: "example-b://page", Package: "com.example.b", AppName: "B"},
+		{Package: "com.example.c"},
+	}
+	if !reflect.DeepEqual(appLink.Android, android) {
+		t.Errorf("unexpected Android targets: %+v", appLink.Android)
+	}
+	ios := []ogp.AppLinkTargetData{{AppStoreID: "1", AppName: "One"}, {AppStoreID: "2", URL: "two://page"}}
+	if !reflect.DeepEqual(appLink.IOS, ios) {
+		t.Errorf("unexpected iOS targets: %+v", appLink.IOS)
+	}
+}
@@ -2,160 +2,337 @@ package ogp
 
 import (
 	"html/template"
+	"io"
 	"time"
 )
 
-// ArticleBuilder builds a `book` object.
+// ArticleData holds the properties of an `article` object.
+type ArticleData struct {
+	WebsiteData
+	PublishedTime  *time.Time    `json:"published_time,omitempty"`
+	ModifiedTime   *time.Time    `json:"modified_time,omitempty"`
+	ExpirationTime *time.Time    `json:"expiration_time,omitempty"`
+	Section        string        `json:"section,omitempty"`
+	Tags           []string      `json:"tags,omitempty"`
+	Authors        []ProfileData `json:"authors,omitempty"`
+	Publisher      string        `json:"publisher,omitempty"`
+}
+
+// ArticleBuilder builds an `article` object.
 type ArticleBuilder struct {
-	WebsiteBuilder
-	publishedTime  *time.Time
-	modifiedTime   *time.Time
-	expirationTime *time.Time
-	section        string
-	tags           []string
-	authors        metaBuilder
+	data ArticleData
 }
 
 // Title sets the `article:title` property.
 func (b *ArticleBuilder) Title(title string) *ArticleBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `article:url` property.
 func (b *ArticleBuilder) URL(url string) *ArticleBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `article:description` property.
 func (b *ArticleBuilder) Description(description string) *ArticleBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `article:determiner` property.
 func (b *ArticleBuilder) Determiner(determiner string) *ArticleBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `article:locale` or adds a new `article:locale:alternate` property.
 func (b *ArticleBuilder) Locale(locale string) *ArticleBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `article:site_name` property.
 func (b *ArticleBuilder) SiteName(siteName string) *ArticleBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `article:image` property.
 func (b *ArticleBuilder) Image(image *ImageBuilder) *ArticleBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `article:video` property.
 func (b *ArticleBuilder) Video(video *VideoBuilder) *ArticleBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `article:audio` property.
 func (b *ArticleBuilder) Audio(audio *AudioBuilder) *ArticleBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// SeeAlso adds a new `article:see_also` property, the URL of a related page.
+func (b *ArticleBuilder) SeeAlso(url string) *ArticleBuilder {
+	b.data.SeeAlso = append(b.data.SeeAlso, url)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *ArticleBuilder) Facebook(facebook *FacebookBuilder) *ArticleBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *ArticleBuilder) AppLink(appLink *AppLinkBuilder) *ArticleBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *ArticleBuilder) Restrictions(restrictions *RestrictionsBuilder) *ArticleBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *ArticleBuilder) TTL(ttl time.Duration) *ArticleBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *ArticleBuilder) UpdatedTime(updatedTime time.Time) *ArticleBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *ArticleBuilder) RichAttachment(richAttachment bool) *ArticleBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *ArticleBuilder) Property(ns, prop, content string) *ArticleBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *ArticleBuilder) Namespace(prefix, uri string) *ArticleBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *ArticleBuilder) Defaults(defaults *Defaults) *ArticleBuilder {
+	b.data.defaults = defaults
 	return b
 }
 
 // PublishedTime sets the `article:published_time` property.
 func (b *ArticleBuilder) PublishedTime(publishedTime time.Time) *ArticleBuilder {
-	b.publishedTime = &publishedTime
+	b.data.PublishedTime = &publishedTime
 	return b
 }
 
 // ModifiedTime sets the `article:modified_time` property.
 func (b *ArticleBuilder) ModifiedTime(modifiedTime time.Time) *ArticleBuilder {
-	b.modifiedTime = &modifiedTime
+	b.data.ModifiedTime = &modifiedTime
 	return b
 }
 
 // ExpirationTime sets the `article:expiration_time` property.
 func (b *ArticleBuilder) ExpirationTime(expirationTime time.Time) *ArticleBuilder {
-	b.expirationTime = &expirationTime
+	b.data.ExpirationTime = &expirationTime
 	return b
 }
 
 // Section sets the `article:section` property.
 func (b *ArticleBuilder) Section(section string) *ArticleBuilder {
-	b.section = section
+	b.data.Section = section
 	return b
 }
 
 // Tag adds a new `article:tag` property.
 func (b *ArticleBuilder) Tag(tag string) *ArticleBuilder {
-	b.tags = append(b.tags, tag)
+	b.data.Tags = append(b.data.Tags, tag)
 	return b
 }
 
 // Author adds a new `article:author` property.
-func (b *ArticleBuilder) Author(author *ProfileBuilder) *ArticleBuilder {
-	b.authors.Include(author.meta("article:author"))
+func (b *ArticleBuilder) Author(author Object) *ArticleBuilder {
+	b.data.Authors = append(b.data.Authors, profileOf(author))
+	return b
+}
+
+// Publisher sets the `article:publisher` property, the URL of the Facebook
+// page of the publisher.
+func (b *ArticleBuilder) Publisher(url string) *ArticleBuilder {
+	b.data.Publisher = url
 	return b
 }
 
+// Data returns the properties of the `article` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *ArticleBuilder) Data() *ArticleData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the `article`
+// object.
+func (d ArticleData) Builder() *ArticleBuilder {
+	return &ArticleBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `article`.
+func (b *ArticleBuilder) Type() string {
+	return "article"
+}
+
+// Validate checks the `article` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *ArticleBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
 // HTML renders the `article` object to be used in HTML templates.
 func (b *ArticleBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `article` object as a schema.org `Article` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *ArticleBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `article` object as HTML markup.
+func (b *ArticleBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `article` object, in rendering
+// order.
+func (b *ArticleBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `article` object.
+func (b *ArticleBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `article` object as HTML markup into w.
+func (b *ArticleBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *ArticleBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "article")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
-	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
-	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add("og", "locale", locale)
-		} else {
-			mb.Add("og", "locale:alternate", locale)
-		}
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *ArticleData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "article")
+	if d.PublishedTime != nil {
+		mb.Add("article", "published_time", d.PublishedTime.Format(time.RFC3339))
 	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
+	if d.ModifiedTime != nil {
+		mb.Add("article", "modified_time", d.ModifiedTime.Format(time.RFC3339))
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+	if d.ExpirationTime != nil {
+		mb.Add("article", "expiration_time", d.ExpirationTime.Format(time.RFC3339))
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+	if d.Section != "" {
+		mb.Add("article", "section", d.Section)
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+	for _, tag := range d.Tags {
+		mb.Add("article", "tag", tag)
 	}
-	if b.publishedTime != nil {
-		mb.Add("article", "published_time", b.publishedTime.Format(time.RFC3339))
+	for i := range d.Authors {
+		d.Authors[i].meta(mb, "article:author")
 	}
-	if b.modifiedTime != nil {
-		mb.Add("article", "modified_time", b.modifiedTime.Format(time.RFC3339))
+	if d.Publisher != "" {
+		mb.Add("article", "publisher", d.Publisher)
 	}
-	if b.expirationTime != nil {
-		mb.Add("article", "expiration_time", b.expirationTime.Format(time.RFC3339))
+}
+
+func (d *ArticleData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	for i := range d.Authors {
+		d.Authors[i].validate(v, join("", "article:author", i), "article:author")
 	}
-	if b.section != "" {
-		mb.Add("article", "section", b.section)
+	v.url("", "article:publisher", d.Publisher)
+}
+
+func (d *ArticleData) linkedData() linkedData {
+	ld := d.baseLinkedData("Article")
+	ld.set("headline", d.Title)
+	ld.set("datePublished", d.PublishedTime)
+	ld.set("dateModified", d.ModifiedTime)
+	ld.set("expires", d.ExpirationTime)
+	ld.set("articleSection", d.Section)
+	ld.set("keywords", d.Tags)
+	ld.set("author", peopleLinkedData(d.Authors))
+	if d.Publisher != "" {
+		ld.set("publisher", linkedData{"@type": "Organization", "url": d.Publisher})
 	}
-	for _, tag := range b.tags {
-		mb.Add("article", "tag", tag)
+	return ld
+}
+
+func (d *ArticleData) decode(g *group) {
+	switch g.Name {
+	case "article:published_time":
+		if t, ok := parseTime(g.Content); ok {
+			d.PublishedTime = &t
+		}
+	case "article:modified_time":
+		if t, ok := parseTime(g.Content); ok {
+			d.ModifiedTime = &t
+		}
+	case "article:expiration_time":
+		if t, ok := parseTime(g.Content); ok {
+			d.ExpirationTime = &t
+		}
+	case "article:section":
+		d.Section = g.Content
+	case "article:tag":
+		d.Tags = append(d.Tags, g.Content)
+	case "article:author":
+		d.Authors = append(d.Authors, decodeProfile(g))
+	case "article:publisher":
+		d.Publisher = g.Content
+	default:
+		d.baseDecode(g, "og:")
 	}
-	mb.Include(&b.authors)
-	return &mb
 }
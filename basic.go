@@ -2,177 +2,295 @@ package ogp
 
 // Image -----------------------------------------------------------------------
 
+// ImageData holds the properties of an `og:image` object.
+type ImageData struct {
+	URL       string `json:"url,omitempty"`
+	SecureURL string `json:"secure_url,omitempty"`
+	MIME      string `json:"type,omitempty"`
+	Alt       string `json:"alt,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+}
+
 // ImageBuilder builds an `og:image` object.
 type ImageBuilder struct {
-	url       string
-	secureURL string
-	mime      string
-	alt       string
-	width     int
-	height    int
+	data ImageData
 }
 
 // URL sets the `og:image:url` property.
 func (b *ImageBuilder) URL(url string) *ImageBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // SecureURL sets the `og:image:secure_url` property.
 func (b *ImageBuilder) SecureURL(url string) *ImageBuilder {
-	b.secureURL = url
+	b.data.SecureURL = url
 	return b
 }
 
 // MIME sets the `og:image:type` property.
 func (b *ImageBuilder) MIME(mime string) *ImageBuilder {
-	b.mime = mime
+	b.data.MIME = mime
 	return b
 }
 
 // Alt sets the `og:image:alt` property.
 func (b *ImageBuilder) Alt(alt string) *ImageBuilder {
-	b.alt = alt
+	b.data.Alt = alt
 	return b
 }
 
 // Width sets the `og:image:width` property.
 func (b *ImageBuilder) Width(width int) *ImageBuilder {
-	b.width = width
+	b.data.Width = width
 	return b
 }
 
 // Height sets the `og:image:height` property.
 func (b *ImageBuilder) Height(height int) *ImageBuilder {
-	b.height = height
+	b.data.Height = height
 	return b
 }
 
-func (b *ImageBuilder) meta(ns string) *metaBuilder {
-	var mb metaBuilder
-	mb.Add(ns, "image", b.url)
-	if b.secureURL != "" {
-		mb.Add(ns, "image:secure_url", b.secureURL)
+// Data returns the properties of the `og:image` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *ImageBuilder) Data() *ImageData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the `og:image`
+// object.
+func (d ImageData) Builder() *ImageBuilder {
+	return &ImageBuilder{data: d}
+}
+
+func (d *ImageData) meta(mb *metaBuilder, ns string) {
+	mb.Add(ns, "image", d.URL)
+	if d.SecureURL != "" {
+		mb.Add(ns, "image:secure_url", d.SecureURL)
 	}
-	if b.mime != "" {
-		mb.Add(ns, "image:type", b.mime)
+	if d.MIME != "" {
+		mb.Add(ns, "image:type", d.MIME)
 	}
-	if b.alt != "" {
-		mb.Add(ns, "image:alt", b.alt)
+	if d.Alt != "" {
+		mb.Add(ns, "image:alt", d.Alt)
 	}
-	if b.width > 0 {
-		mb.Add(ns, "image:width", b.width)
+	if d.Width > 0 {
+		mb.AddInt(ns, "image:width", d.Width)
 	}
-	if b.height > 0 {
-		mb.Add(ns, "image:height", b.height)
+	if d.Height > 0 {
+		mb.AddInt(ns, "image:height", d.Height)
 	}
-	return &mb
+}
+
+func (d *ImageData) validate(v *validator, path, ns string) {
+	v.required(path, name(ns, "image"), d.URL)
+	v.url(path, name(ns, "image"), d.URL)
+	v.url(path, name(ns, "image:secure_url"), d.SecureURL)
+	v.nonNegative(path, name(ns, "image:width"), d.Width)
+	v.nonNegative(path, name(ns, "image:height"), d.Height)
+}
+
+func decodeImage(g *group) ImageData {
+	d := ImageData{URL: g.Content}
+	for _, p := range g.props {
+		switch p.Name {
+		case "secure_url":
+			d.SecureURL = p.Content
+		case "type":
+			d.MIME = p.Content
+		case "alt":
+			d.Alt = p.Content
+		case "width":
+			d.Width = parseInt(p.Content)
+		case "height":
+			d.Height = parseInt(p.Content)
+		}
+	}
+	return d
 }
 
 // Video -----------------------------------------------------------------------
 
-// VideoBuilder builds an `og:video` object.
+// VideoData holds the properties of a `og:video` object.
+type VideoData struct {
+	URL       string `json:"url,omitempty"`
+	SecureURL string `json:"secure_url,omitempty"`
+	MIME      string `json:"type,omitempty"`
+	Alt       string `json:"alt,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+}
+
+// VideoBuilder builds a `og:video` object.
 type VideoBuilder struct {
-	url       string
-	secureURL string
-	mime      string
-	alt       string
-	width     int
-	height    int
+	data VideoData
 }
 
 // URL sets the `og:video:url` property.
 func (b *VideoBuilder) URL(url string) *VideoBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // SecureURL sets the `og:video:secure_url` property.
 func (b *VideoBuilder) SecureURL(url string) *VideoBuilder {
-	b.secureURL = url
+	b.data.SecureURL = url
 	return b
 }
 
 // MIME sets the `og:video:type` property.
 func (b *VideoBuilder) MIME(mime string) *VideoBuilder {
-	b.mime = mime
+	b.data.MIME = mime
 	return b
 }
 
 // Alt sets the `og:video:alt` property.
 func (b *VideoBuilder) Alt(alt string) *VideoBuilder {
-	b.alt = alt
+	b.data.Alt = alt
 	return b
 }
 
 // Width sets the `og:video:width` property.
 func (b *VideoBuilder) Width(width int) *VideoBuilder {
-	b.width = width
+	b.data.Width = width
 	return b
 }
 
 // Height sets the `og:video:height` property.
 func (b *VideoBuilder) Height(height int) *VideoBuilder {
-	b.height = height
+	b.data.Height = height
 	return b
 }
 
-func (b *VideoBuilder) meta(ns string) *metaBuilder {
-	var mb metaBuilder
-	mb.Add(ns, "video", b.url)
-	if b.secureURL != "" {
-		mb.Add(ns, "video:secure_url", b.secureURL)
+// Data returns the properties of the `og:video` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *VideoBuilder) Data() *VideoData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the `og:video`
+// object.
+func (d VideoData) Builder() *VideoBuilder {
+	return &VideoBuilder{data: d}
+}
+
+func (d *VideoData) meta(mb *metaBuilder, ns string) {
+	mb.Add(ns, "video", d.URL)
+	if d.SecureURL != "" {
+		mb.Add(ns, "video:secure_url", d.SecureURL)
 	}
-	if b.mime != "" {
-		mb.Add(ns, "video:type", b.mime)
+	if d.MIME != "" {
+		mb.Add(ns, "video:type", d.MIME)
 	}
-	if b.alt != "" {
-		mb.Add(ns, "video:alt", b.alt)
+	if d.Alt != "" {
+		mb.Add(ns, "video:alt", d.Alt)
 	}
-	if b.width > 0 {
-		mb.Add(ns, "video:width", b.width)
+	if d.Width > 0 {
+		mb.AddInt(ns, "video:width", d.Width)
 	}
-	if b.height > 0 {
-		mb.Add(ns, "video:height", b.height)
+	if d.Height > 0 {
+		mb.AddInt(ns, "video:height", d.Height)
 	}
-	return &mb
+}
+
+func (d *VideoData) validate(v *validator, path, ns string) {
+	v.required(path, name(ns, "video"), d.URL)
+	v.url(path, name(ns, "video"), d.URL)
+	v.url(path, name(ns, "video:secure_url"), d.SecureURL)
+	v.nonNegative(path, name(ns, "video:width"), d.Width)
+	v.nonNegative(path, name(ns, "video:height"), d.Height)
+}
+
+func decodeVideo(g *group) VideoData {
+	d := VideoData{URL: g.Content}
+	for _, p := range g.props {
+		switch p.Name {
+		case "secure_url":
+			d.SecureURL = p.Content
+		case "type":
+			d.MIME = p.Content
+		case "alt":
+			d.Alt = p.Content
+		case "width":
+			d.Width = parseInt(p.Content)
+		case "height":
+			d.Height = parseInt(p.Content)
+		}
+	}
+	return d
 }
 
 // Audio -----------------------------------------------------------------------
 
+// AudioData holds the properties of an `og:audio` object.
+type AudioData struct {
+	URL       string `json:"url,omitempty"`
+	SecureURL string `json:"secure_url,omitempty"`
+	MIME      string `json:"type,omitempty"`
+}
+
 // AudioBuilder builds an `og:audio` object.
 type AudioBuilder struct {
-	url       string
-	secureURL string
-	mime      string
+	data AudioData
 }
 
 // URL sets the `og:audio:url` property.
 func (b *AudioBuilder) URL(url string) *AudioBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // SecureURL sets the `og:audio:secure_url` property.
 func (b *AudioBuilder) SecureURL(url string) *AudioBuilder {
-	b.secureURL = url
+	b.data.SecureURL = url
 	return b
 }
 
 // MIME sets the `og:audio:type` property.
 func (b *AudioBuilder) MIME(mime string) *AudioBuilder {
-	b.mime = mime
+	b.data.MIME = mime
 	return b
 }
 
-func (b *AudioBuilder) meta(ns string) *metaBuilder {
-	var mb metaBuilder
-	mb.Add(ns, "audio", b.url)
-	if b.secureURL != "" {
-		mb.Add(ns, "audio:secure_url", b.secureURL)
+// Data returns the properties of the `og:audio` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *AudioBuilder) Data() *AudioData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the `og:audio`
+// object.
+func (d AudioData) Builder() *AudioBuilder {
+	return &AudioBuilder{data: d}
+}
+
+func (d *AudioData) meta(mb *metaBuilder, ns string) {
+	mb.Add(ns, "audio", d.URL)
+	if d.SecureURL != "" {
+		mb.Add(ns, "audio:secure_url", d.SecureURL)
+	}
+	if d.MIME != "" {
+		mb.Add(ns, "audio:type", d.MIME)
 	}
-	if b.mime != "" {
-		mb.Add(ns, "audio:type", b.mime)
+}
+
+func (d *AudioData) validate(v *validator, path, ns string) {
+	v.required(path, name(ns, "audio"), d.URL)
+	v.url(path, name(ns, "audio"), d.URL)
+	v.url(path, name(ns, "audio:secure_url"), d.SecureURL)
+}
+
+func decodeAudio(g *group) AudioData {
+	d := AudioData{URL: g.Content}
+	for _, p := range g.props {
+		switch p.Name {
+		case "secure_url":
+			d.SecureURL = p.Content
+		case "type":
+			d.MIME = p.Content
+		}
 	}
-	return &mb
+	return d
 }
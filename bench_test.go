@@ -0,0 +1,76 @@
+package ogp_test
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+type benchmarkObject struct {
+	name   string
+	object ogp.Object
+}
+
+func benchmarkObjects() []benchmarkObject {
+	date := time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)
+	image := ogp.Image().URL("http://example.com/image.jpg").MIME("image/jpeg").Width(1200).Height(630)
+	profile := ogp.Profile().URL("http://example.com/profile/jsmith").FirstName("John").LastName("Smith")
+	return []benchmarkObject{
+		{"Website", ogp.Website().Title("Example").URL("http://example.com").Description("An example website").
+			Locale("en_US").SiteName("Example").Image(image)},
+		{"Article", ogp.Article().Title("Article").URL("http://example.com/article").Image(image).
+			PublishedTime(date).Section("News").Tag("news").Tag("example").Author(profile)},
+		{"Book", ogp.Book().Title("Book").URL("http://example.com/book").Image(image).
+			ISBN("9780174325482").ReleaseDate(ogp.TimeOf(date)).Author(profile)},
+		{"Profile", ogp.Profile().Title("John Smith").URL("http://example.com/profile/jsmith").Image(image).
+			FirstName("John").LastName("Smith").Username("jsmith")},
+		{"Song", ogp.Song().Title("Song").URL("http://example.com/song").Image(image).Duration(185*time.Second).
+			Album("http://example.com/album", 1, 3).Musician(profile)},
+		{"Album", ogp.Album().Title("Album").URL("http://example.com/album").Image(image).ReleaseDate(ogp.TimeOf(date)).
+			Song("http://example.com/song/1", 1, 1).Song("http://example.com/song/2", 1, 2).Musician(profile)},
+		{"Playlist", ogp.Playlist().Title("Playlist").URL("http://example.com/playlist").Image(image).
+			Song("http://example.com/song/1", 0, 1).Song("http://example.com/song/2", 0, 2).Creator(profile)},
+		{"RadioStation", ogp.RadioStation().Title("Radio").URL("http://example.com/radio").Image(image).Creator(profile)},
+		{"Movie", ogp.Movie().Title("Movie").URL("http://example.com/movie").Image(image).Duration(2*time.Hour).
+			ReleaseDate(ogp.TimeOf(date)).Actor(profile, "Hero").Director(profile).Writer(profile)},
+		{"TVShow", ogp.TVShow().Title("Show").URL("http://example.com/show").Image(image).Duration(30*time.Minute).
+			Actor(profile, "Hero")},
+		{"Episode", ogp.Episode().Title("Episode").URL("http://example.com/show/1").Image(image).Duration(30*time.Minute).
+			Actor(profile, "Hero").Series(ogp.TVShow().Title("Show").URL("http://example.com/show"))},
+		{"VideoOther", ogp.VideoOther().Title("Clip").URL("http://example.com/clip").Image(image).Duration(1 * time.Minute)},
+		{"Product", ogp.Product().Title("Shoes").URL("http://example.com/shoes").Image(image).
+			Price(ogp.NewDecimal(4990, 2), "EUR").Availability(ogp.AvailabilityInStock).Brand("Acme")},
+		{"ProductGroup", ogp.ProductGroup().Title("Shoes").URL("http://example.com/shoes").Image(image).
+			RetailerGroupID("SH").Brand("Acme")},
+		{"Place", ogp.Place().Title("Tower").URL("http://example.com/tower").Image(image).Location(48.8584, 2.2945)},
+		{"Business", ogp.Business().Title("Bakery").URL("http://example.com/bakery").Image(image).
+			Contact(ogp.Contact().StreetAddress("1 Main St").Locality("Springfield").PostalCode("12345").CountryName("USA")).
+			Hours(time.Monday, ogp.TimeOfDay{Hour: 7}, ogp.TimeOfDay{Hour: 19})},
+	}
+}
+
+func BenchmarkHTML(b *testing.B) {
+	for _, test := range benchmarkObjects() {
+		object := test.object
+		b.Run(test.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = object.HTML()
+			}
+		})
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	for _, test := range benchmarkObjects() {
+		object := test.object
+		b.Run(test.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				object.WriteTo(ioutil.Discard)
+			}
+		})
+	}
+}
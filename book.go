@@ -2,140 +2,285 @@ package ogp
 
 import (
 	"html/template"
+	"io"
 	"time"
 )
 
+// BookData holds the properties of a `book` object.
+type BookData struct {
+	WebsiteData
+	ISBN        string        `json:"isbn,omitempty"`
+	ReleaseDate *DateTime     `json:"release_date,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Authors     []ProfileData `json:"authors,omitempty"`
+}
+
 // BookBuilder builds a `book` object.
 type BookBuilder struct {
-	WebsiteBuilder
-	isbn        string
-	releaseDate *time.Time
-	tags        []string
-	authors     metaBuilder
+	data BookData
 }
 
 // Title sets the `book:title` property.
 func (b *BookBuilder) Title(title string) *BookBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `book:url` property.
 func (b *BookBuilder) URL(url string) *BookBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `book:description` property.
 func (b *BookBuilder) Description(description string) *BookBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `book:determiner` property.
 func (b *BookBuilder) Determiner(determiner string) *BookBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `book:locale` or adds a new `book:locale:alternate` property.
 func (b *BookBuilder) Locale(locale string) *BookBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `book:site_name` property.
 func (b *BookBuilder) SiteName(siteName string) *BookBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `book:image` property.
 func (b *BookBuilder) Image(image *ImageBuilder) *BookBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `book:video` property.
 func (b *BookBuilder) Video(video *VideoBuilder) *BookBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `book:audio` property.
 func (b *BookBuilder) Audio(audio *AudioBuilder) *BookBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *BookBuilder) Facebook(facebook *FacebookBuilder) *BookBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *BookBuilder) AppLink(appLink *AppLinkBuilder) *BookBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *BookBuilder) Restrictions(restrictions *RestrictionsBuilder) *BookBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *BookBuilder) TTL(ttl time.Duration) *BookBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *BookBuilder) UpdatedTime(updatedTime time.Time) *BookBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *BookBuilder) RichAttachment(richAttachment bool) *BookBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *BookBuilder) Property(ns, prop, content string) *BookBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *BookBuilder) Namespace(prefix, uri string) *BookBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *BookBuilder) Defaults(defaults *Defaults) *BookBuilder {
+	b.data.defaults = defaults
 	return b
 }
 
 // ISBN sets the `book:isbn` property.
 func (b *BookBuilder) ISBN(isbn string) *BookBuilder {
-	b.isbn = isbn
+	b.data.ISBN = isbn
 	return b
 }
 
-// ReleaseDate sets the `book:release_date` property.
-func (b *BookBuilder) ReleaseDate(releaseDate time.Time) *BookBuilder {
-	b.releaseDate = &releaseDate
+// ReleaseDate sets the `book:release_date` property, either a
+// calendar date, e.g. NewDate(2020, time.May, 1), or an instant, e.g.
+// TimeOf(time.Now()).
+func (b *BookBuilder) ReleaseDate(releaseDate DateTime) *BookBuilder {
+	b.data.ReleaseDate = &releaseDate
 	return b
 }
 
 // Tag adds a new `book:tag` property.
 func (b *BookBuilder) Tag(tag string) *BookBuilder {
-	b.tags = append(b.tags, tag)
+	b.data.Tags = append(b.data.Tags, tag)
 	return b
 }
 
 // Author adds a new `book:author` property.
-func (b *BookBuilder) Author(author *ProfileBuilder) *BookBuilder {
-	b.authors.Include(author.meta("book:author"))
+func (b *BookBuilder) Author(author Object) *BookBuilder {
+	b.data.Authors = append(b.data.Authors, profileOf(author))
 	return b
 }
 
+// Data returns the properties of the `book` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *BookBuilder) Data() *BookData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the `book`
+// object.
+func (d BookData) Builder() *BookBuilder {
+	return &BookBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `book`.
+func (b *BookBuilder) Type() string {
+	return "book"
+}
+
+// Validate checks the `book` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *BookBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
 // HTML renders the `book` object to be used in HTML templates.
 func (b *BookBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `book` object as a schema.org `Book` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *BookBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `book` object as HTML markup.
+func (b *BookBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `book` object, in rendering
+// order.
+func (b *BookBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `book` object.
+func (b *BookBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `book` object as HTML markup into w.
+func (b *BookBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *BookBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "book")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
-	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
-	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add("og", "locale", locale)
-		} else {
-			mb.Add("og", "locale:alternate", locale)
-		}
-	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
-	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *BookData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "book")
+	if d.ISBN != "" {
+		mb.Add("book", "isbn", d.ISBN)
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+	if d.ReleaseDate != nil {
+		mb.Add("book", "release_date", d.ReleaseDate.String())
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+	for _, tag := range d.Tags {
+		mb.Add("book", "tag", tag)
 	}
-	if b.isbn != "" {
-		mb.Add("book", "isbn", b.isbn)
+	for i := range d.Authors {
+		d.Authors[i].meta(mb, "book:author")
 	}
-	if b.releaseDate != nil {
-		mb.Add("book", "release_date", b.releaseDate.Format(time.RFC3339))
+}
+
+func (d *BookData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	for i := range d.Authors {
+		d.Authors[i].validate(v, join("", "book:author", i), "book:author")
 	}
-	for _, tag := range b.tags {
-		mb.Add("book", "tag", tag)
+}
+
+func (d *BookData) linkedData() linkedData {
+	ld := d.baseLinkedData("Book")
+	ld.set("isbn", d.ISBN)
+	ld.set("datePublished", d.ReleaseDate)
+	ld.set("keywords", d.Tags)
+	ld.set("author", peopleLinkedData(d.Authors))
+	return ld
+}
+
+func (d *BookData) decode(g *group) {
+	switch g.Name {
+	case "book:isbn":
+		d.ISBN = g.Content
+	case "book:release_date":
+		if t, err := ParseDateTime(g.Content); err == nil {
+			d.ReleaseDate = &t
+		}
+	case "book:tag":
+		d.Tags = append(d.Tags, g.Content)
+	case "book:author":
+		d.Authors = append(d.Authors, decodeProfile(g))
+	default:
+		d.baseDecode(g, "og:")
 	}
-	mb.Include(&b.authors)
-	return &mb
 }
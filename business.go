@@ -0,0 +1,567 @@
+package ogp
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BusinessData holds the properties of a `business.business` object.
+type BusinessData struct {
+	WebsiteData
+	Contact  *ContactData        `json:"contact,omitempty"`
+	Hours    []BusinessHoursData `json:"hours,omitempty"`
+	Location *LocationData       `json:"location,omitempty"`
+}
+
+// BusinessHoursData holds the `business:hours:*` properties, the opening
+// hours of a business on a day of the week. The end may be earlier than the
+// start for businesses open past midnight.
+type BusinessHoursData struct {
+	Day   time.Weekday `json:"day"`
+	Start TimeOfDay    `json:"start"`
+	End   TimeOfDay    `json:"end"`
+}
+
+// BusinessBuilder builds a `business.business` object.
+type BusinessBuilder struct {
+	data BusinessData
+}
+
+// Title sets the `business:title` property.
+func (b *BusinessBuilder) Title(title string) *BusinessBuilder {
+	b.data.Title = title
+	return b
+}
+
+// URL sets the `business:url` property.
+func (b *BusinessBuilder) URL(url string) *BusinessBuilder {
+	b.data.URL = url
+	return b
+}
+
+// Description sets the `business:description` property.
+func (b *BusinessBuilder) Description(description string) *BusinessBuilder {
+	b.data.Description = description
+	return b
+}
+
+// Determiner sets the `business:determiner` property.
+func (b *BusinessBuilder) Determiner(determiner string) *BusinessBuilder {
+	b.data.Determiner = determiner
+	return b
+}
+
+// Locale sets the `business:locale` or adds a new `business:locale:alternate` property.
+func (b *BusinessBuilder) Locale(locale string) *BusinessBuilder {
+	b.data.Locales = append(b.data.Locales, locale)
+	return b
+}
+
+// SiteName sets the `business:site_name` property.
+func (b *BusinessBuilder) SiteName(siteName string) *BusinessBuilder {
+	b.data.SiteName = siteName
+	return b
+}
+
+// Image adds a new `business:image` property.
+func (b *BusinessBuilder) Image(image *ImageBuilder) *BusinessBuilder {
+	b.data.Images = append(b.data.Images, image.data)
+	return b
+}
+
+// Video adds a new `business:video` property.
+func (b *BusinessBuilder) Video(video *VideoBuilder) *BusinessBuilder {
+	b.data.Videos = append(b.data.Videos, video.data)
+	return b
+}
+
+// Audio adds a new `business:audio` property.
+func (b *BusinessBuilder) Audio(audio *AudioBuilder) *BusinessBuilder {
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *BusinessBuilder) Facebook(facebook *FacebookBuilder) *BusinessBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *BusinessBuilder) AppLink(appLink *AppLinkBuilder) *BusinessBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *BusinessBuilder) Restrictions(restrictions *RestrictionsBuilder) *BusinessBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *BusinessBuilder) TTL(ttl time.Duration) *BusinessBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *BusinessBuilder) UpdatedTime(updatedTime time.Time) *BusinessBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *BusinessBuilder) RichAttachment(richAttachment bool) *BusinessBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *BusinessBuilder) Property(ns, prop, content string) *BusinessBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *BusinessBuilder) Namespace(prefix, uri string) *BusinessBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *BusinessBuilder) Defaults(defaults *Defaults) *BusinessBuilder {
+	b.data.defaults = defaults
+	return b
+}
+
+// Contact sets the `business:contact_data:*` properties.
+func (b *BusinessBuilder) Contact(contact *ContactBuilder) *BusinessBuilder {
+	data := contact.data
+	b.data.Contact = &data
+	return b
+}
+
+// Hours adds new `business:hours:day`, `business:hours:start` and
+// `business:hours:end` properties.
+func (b *BusinessBuilder) Hours(day time.Weekday, start, end TimeOfDay) *BusinessBuilder {
+	b.data.Hours = append(b.data.Hours, BusinessHoursData{Day: day, Start: start, End: end})
+	return b
+}
+
+// Location sets the `place:location:latitude` and `place:location:longitude`
+// properties, in decimal degrees.
+func (b *BusinessBuilder) Location(latitude, longitude float64) *BusinessBuilder {
+	b.data.location().Latitude = latitude
+	b.data.location().Longitude = longitude
+	return b
+}
+
+// Altitude sets the `place:location:altitude` property, in meters.
+func (b *BusinessBuilder) Altitude(altitude float64) *BusinessBuilder {
+	b.data.location().Altitude = &altitude
+	return b
+}
+
+// Data returns the properties of the `business.business` object. Changes made
+// to the returned value are reflected in the builder.
+func (b *BusinessBuilder) Data() *BusinessData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `business.business` object.
+func (d BusinessData) Builder() *BusinessBuilder {
+	return &BusinessBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `business.business`.
+func (b *BusinessBuilder) Type() string {
+	return "business.business"
+}
+
+// Validate checks the `business.business` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *BusinessBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
+// HTML renders the `business.business` object to be used in HTML templates.
+func (b *BusinessBuilder) HTML() template.HTML {
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `business.business` object as a schema.org `LocalBusiness` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *BusinessBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `business.business` object as HTML markup.
+func (b *BusinessBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `business.business` object, in rendering
+// order.
+func (b *BusinessBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `business.business` object.
+func (b *BusinessBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `business.business` object as HTML markup into w.
+func (b *BusinessBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
+}
+
+func (b *BusinessBuilder) meta() *metaBuilder {
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *BusinessData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "business.business")
+	d.Contact.meta(mb)
+	for _, hours := range d.Hours {
+		mb.Add("business", "hours:day", strings.ToLower(hours.Day.String()))
+		mb.Add("business", "hours:start", hours.Start.String())
+		mb.Add("business", "hours:end", hours.End.String())
+	}
+	d.Location.meta(mb)
+}
+
+func (d *BusinessData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	contact := d.Contact
+	if contact == nil {
+		contact = &ContactData{}
+	}
+	contact.validate(v)
+	for i, hours := range d.Hours {
+		path := join("", "business:hours", i)
+		if hours.Day < time.Sunday || hours.Day > time.Saturday {
+			v.add(path, "business:hours:day", RuleValue)
+		}
+		if !hours.Start.valid() {
+			v.add(path, "business:hours:start", RuleRange)
+		}
+		if !hours.End.valid() {
+			v.add(path, "business:hours:end", RuleRange)
+		}
+	}
+	d.Location.validate(v, "")
+}
+
+func (d *BusinessData) linkedData() linkedData {
+	ld := d.baseLinkedData("LocalBusiness")
+	d.Contact.setLinkedData(ld)
+	var hours []linkedData
+	for _, h := range d.Hours {
+		hours = append(hours, linkedData{
+			"@type":     "OpeningHoursSpecification",
+			"dayOfWeek": "https://schema.org/" + h.Day.String(),
+			"opens":     h.Start.String(),
+			"closes":    h.End.String(),
+		})
+	}
+	ld.set("openingHoursSpecification", hours)
+	d.Location.setLinkedData(ld)
+	return ld
+}
+
+// decode decodes the property g. validDay tells whether the last
+// `business:hours:day` was valid, so that the start and end of an invalid day
+// are ignored rather than applied to the previous day.
+func (d *BusinessData) decode(g *group, validDay *bool) {
+	switch g.Name {
+	case "business:hours:day":
+		day, ok := parseWeekday(g.Content)
+		if ok {
+			d.Hours = append(d.Hours, BusinessHoursData{Day: day})
+		}
+		*validDay = ok
+	case "business:hours:start":
+		if *validDay {
+			d.Hours[len(d.Hours)-1].Start, _ = ParseTimeOfDay(g.Content)
+		}
+	case "business:hours:end":
+		if *validDay {
+			d.Hours[len(d.Hours)-1].End, _ = ParseTimeOfDay(g.Content)
+		}
+	default:
+		if decodeLocation(&d.Location, g) {
+			break
+		}
+		if strings.HasPrefix(g.Name, "business:contact_data:") {
+			if d.Contact == nil {
+				d.Contact = &ContactData{}
+			}
+			if d.Contact.decode(g) {
+				break
+			}
+		}
+		d.baseDecode(g, "og:")
+	}
+}
+
+func (d *BusinessData) location() *LocationData {
+	if d.Location == nil {
+		d.Location = &LocationData{}
+	}
+	return d.Location
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(s, day.String()) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// Contact ---------------------------------------------------------------------
+
+// ContactData holds the `business:contact_data:*` properties of a business.
+type ContactData struct {
+	StreetAddress string `json:"street_address,omitempty"`
+	Locality      string `json:"locality,omitempty"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code,omitempty"`
+	CountryName   string `json:"country_name,omitempty"`
+	Email         string `json:"email,omitempty"`
+	PhoneNumber   string `json:"phone_number,omitempty"`
+	Website       string `json:"website,omitempty"`
+}
+
+// ContactBuilder builds the `business:contact_data:*` properties of a
+// business.
+type ContactBuilder struct {
+	data ContactData
+}
+
+// StreetAddress sets the `business:contact_data:street_address` property.
+func (b *ContactBuilder) StreetAddress(streetAddress string) *ContactBuilder {
+	b.data.StreetAddress = streetAddress
+	return b
+}
+
+// Locality sets the `business:contact_data:locality` property, e.g. the
+// city.
+func (b *ContactBuilder) Locality(locality string) *ContactBuilder {
+	b.data.Locality = locality
+	return b
+}
+
+// Region sets the `business:contact_data:region` property, e.g. the state.
+func (b *ContactBuilder) Region(region string) *ContactBuilder {
+	b.data.Region = region
+	return b
+}
+
+// PostalCode sets the `business:contact_data:postal_code` property.
+func (b *ContactBuilder) PostalCode(postalCode string) *ContactBuilder {
+	b.data.PostalCode = postalCode
+	return b
+}
+
+// CountryName sets the `business:contact_data:country_name` property.
+func (b *ContactBuilder) CountryName(countryName string) *ContactBuilder {
+	b.data.CountryName = countryName
+	return b
+}
+
+// Email sets the `business:contact_data:email` property.
+func (b *ContactBuilder) Email(email string) *ContactBuilder {
+	b.data.Email = email
+	return b
+}
+
+// PhoneNumber sets the `business:contact_data:phone_number` property.
+func (b *ContactBuilder) PhoneNumber(phoneNumber string) *ContactBuilder {
+	b.data.PhoneNumber = phoneNumber
+	return b
+}
+
+// Website sets the `business:contact_data:website` property.
+func (b *ContactBuilder) Website(url string) *ContactBuilder {
+	b.data.Website = url
+	return b
+}
+
+// Data returns the `business:contact_data:*` properties. Changes made to the
+// returned value are reflected in the builder.
+func (b *ContactBuilder) Data() *ContactData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the `business:contact_data:*`
+// properties.
+func (d ContactData) Builder() *ContactBuilder {
+	return &ContactBuilder{data: d}
+}
+
+// fields returns the properties in rendering order, along with their names.
+func (d *ContactData) fields() []struct {
+	name  string
+	value *string
+} {
+	return []struct {
+		name  string
+		value *string
+	}{
+		{"street_address", &d.StreetAddress},
+		{"locality", &d.Locality},
+		{"region", &d.Region},
+		{"postal_code", &d.PostalCode},
+		{"country_name", &d.CountryName},
+		{"email", &d.Email},
+		{"phone_number", &d.PhoneNumber},
+		{"website", &d.Website},
+	}
+}
+
+func (d *ContactData) meta(mb *metaBuilder) {
+	if d == nil {
+		return
+	}
+	for _, field := range d.fields() {
+		if *field.value != "" {
+			mb.Add("business", "contact_data:"+field.name, *field.value)
+		}
+	}
+}
+
+func (d *ContactData) validate(v *validator) {
+	v.required("", "business:contact_data:street_address", d.StreetAddress)
+	v.required("", "business:contact_data:locality", d.Locality)
+	v.required("", "business:contact_data:postal_code", d.PostalCode)
+	v.required("", "business:contact_data:country_name", d.CountryName)
+	v.url("", "business:contact_data:website", d.Website)
+}
+
+func (d *ContactData) setLinkedData(ld linkedData) {
+	if d == nil {
+		return
+	}
+	address := linkedData{"@type": "PostalAddress"}
+	address.set("streetAddress", d.StreetAddress)
+	address.set("addressLocality", d.Locality)
+	address.set("addressRegion", d.Region)
+	address.set("postalCode", d.PostalCode)
+	address.set("addressCountry", d.CountryName)
+	if len(address) > 1 {
+		ld.set("address", address)
+	}
+	ld.set("email", d.Email)
+	ld.set("telephone", d.PhoneNumber)
+	ld.set("sameAs", d.Website)
+}
+
+// decode decodes a `business:contact_data:*` property and reports whether g
+// is one.
+func (d *ContactData) decode(g *group) bool {
+	for _, field := range d.fields() {
+		if g.Name == "business:contact_data:"+field.name {
+			*field.value = g.Content
+			return true
+		}
+	}
+	return false
+}
+
+// Time of day -----------------------------------------------------------------
+
+// TimeOfDay is a time of the day at minute precision, e.g. the opening time
+// of a business. It is rendered as `15:04`, and 24:00 stands for the end of
+// the day.
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+var errTimeOfDaySyntax = errors.New("ogp: invalid time of day syntax")
+
+// ParseTimeOfDay parses a time of the day formatted as `15:04`.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	index := strings.IndexByte(s, ':')
+	if index < 1 || index > 2 || len(s)-index != 3 || strings.IndexFunc(s, func(r rune) bool { return r != ':' && (r < '0' || r > '9') }) >= 0 {
+		return TimeOfDay{}, errTimeOfDaySyntax
+	}
+	hour, err := strconv.Atoi(s[:index])
+	if err != nil {
+		return TimeOfDay{}, errTimeOfDaySyntax
+	}
+	minute, err := strconv.Atoi(s[index+1:])
+	if err != nil {
+		return TimeOfDay{}, errTimeOfDaySyntax
+	}
+	t := TimeOfDay{Hour: hour, Minute: minute}
+	if !t.valid() {
+		return TimeOfDay{}, errors.New("ogp: time of day out of range")
+	}
+	return t, nil
+}
+
+func (t TimeOfDay) String() string {
+	return pad2(t.Hour) + ":" + pad2(t.Minute)
+}
+
+func (t TimeOfDay) valid() bool {
+	if t.Hour == 24 {
+		return t.Minute == 0
+	}
+	return t.Hour >= 0 && t.Hour < 24 && t.Minute >= 0 && t.Minute < 60
+}
+
+// MarshalText encodes t as `15:04`.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a time of the day encoded by MarshalText.
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	parsed, err := ParseTimeOfDay(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+func pad2(i int) string {
+	if i >= 0 && i < 10 {
+		return "0" + strconv.Itoa(i)
+	}
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,460 @@
+package ogp
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is the lifetime of the cached objects whose document sets
+// neither `og:ttl` nor HTTP caching headers.
+const DefaultCacheTTL = time.Hour
+
+// ErrCacheMiss is returned by a Cache that holds no data for a key.
+var ErrCacheMiss = errors.New("ogp: cache miss")
+
+// Cache stores the objects fetched by a CachingFetcher, encoded as opaque
+// data keyed by URL. Its methods may be called concurrently.
+type Cache interface {
+	// Get returns the data stored for key, or ErrCacheMiss.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores data for key.
+	Put(ctx context.Context, key string, data []byte) error
+	// Delete removes the data stored for key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// CachingFetcher fetches objects through a Cache, so that popular URLs are
+// only fetched again once their cached object expires. The lifetime of an
+// object is given by its `og:ttl` and the Cache-Control and Expires headers
+// of its document, the shortest winning. Expired objects are revalidated with the
+// ETag and Last-Modified headers of their document, if any.
+//
+// Concurrent fetches of a URL missing from the cache are collapsed into a
+// single request. It carries the values of the context of the first caller,
+// but is only canceled once all the callers waiting for it are gone. A
+// CachingFetcher must not be modified once used.
+type CachingFetcher struct {
+	// Fetcher fetches the documents, a zero Fetcher if nil.
+	Fetcher *Fetcher
+	// Cache stores the objects.
+	Cache Cache
+	// DefaultTTL is the lifetime of the objects whose document sets neither
+	// `og:ttl` nor HTTP caching headers, DefaultCacheTTL if zero.
+	DefaultTTL time.Duration
+	// MaxTTL caps the lifetime of the objects, if not zero.
+	MaxTTL time.Duration
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+	// OnError is called, if not nil, with the errors returned by the Cache
+	// other than ErrCacheMiss. They don't fail the fetches, the objects being
+	// fetched again instead, but tell a broken Cache apart from an empty
+	// one. It may be called concurrently.
+	OnError func(error)
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is an ongoing fetch, whose entry is shared by the callers waiting
+// for it.
+type flight struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	// waiters is the number of callers waiting for the fetch, guarded by the
+	// mutex of the CachingFetcher.
+	waiters int
+	entry   *cacheEntry
+	err     error
+	// panic is the value the fetch panicked with, if any, raised again by
+	// the callers.
+	panic interface{}
+}
+
+// detachedContext carries the values of a context but neither its deadline
+// nor its cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// cacheEntry is a fetched object as stored in a Cache.
+type cacheEntry struct {
+	URL          string            `json:"url"`
+	Properties   []Property        `json:"properties,omitempty"`
+	Namespaces   []Namespace       `json:"namespaces,omitempty"`
+	Sources      map[string]Source `json:"sources,omitempty"`
+	TTL          time.Duration     `json:"ttl,omitempty"`
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"last_modified,omitempty"`
+	Expires      time.Time         `json:"expires"`
+}
+
+// object decodes the object of e, which is rebuilt for each caller since
+// objects are mutable. An entry without properties records a document
+// without Open Graph properties.
+func (e *cacheEntry) object() (Object, string, error) {
+	if len(e.Properties) == 0 {
+		return nil, e.URL, ErrNoProperties
+	}
+	object, data := decode(e.Properties)
+	data.Namespaces = append([]Namespace(nil), e.Namespaces...)
+	if len(e.Sources) > 0 {
+		data.Sources = make(map[string]Source, len(e.Sources))
+		for name, source := range e.Sources {
+			data.Sources[name] = source
+		}
+	}
+	return object, e.URL, nil
+}
+
+// Fetch returns the object of the document at url, from the cache while it
+// is fresh, along with the URL of the document once redirects are followed.
+// Documents without Open Graph properties are cached as well, and keep
+// returning ErrNoProperties until they expire.
+func (c *CachingFetcher) Fetch(ctx context.Context, url string) (Object, string, error) {
+	entry := c.get(ctx, url)
+	if entry != nil && c.now().Before(entry.Expires) {
+		return entry.object()
+	}
+	c.mu.Lock()
+	f, ok := c.flights[url]
+	if !ok {
+		fctx, cancel := context.WithCancel(detachedContext{ctx})
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		if c.flights == nil {
+			c.flights = make(map[string]*flight)
+		}
+		c.flights[url] = f
+		go c.run(fctx, f, url, entry)
+	}
+	f.waiters++
+	c.mu.Unlock()
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		c.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Later callers start a new fetch rather than joining the
+			// canceled one.
+			f.cancel()
+			if c.flights[url] == f {
+				delete(c.flights, url)
+			}
+		}
+		c.mu.Unlock()
+		return nil, "", ctx.Err()
+	}
+	if f.panic != nil {
+		panic(f.panic)
+	}
+	if f.err != nil {
+		return nil, "", f.err
+	}
+	return f.entry.object()
+}
+
+// run runs the fetch of flight f, and removes it from the ongoing fetches
+// once done, even if it panics.
+func (c *CachingFetcher) run(ctx context.Context, f *flight, url string, stale *cacheEntry) {
+	defer func() {
+		f.panic = recover()
+		c.mu.Lock()
+		if c.flights[url] == f {
+			delete(c.flights, url)
+		}
+		c.mu.Unlock()
+		f.cancel()
+		close(f.done)
+	}()
+	f.entry, f.err = c.fetch(ctx, url, stale)
+}
+
+// get returns the entry cached for url, or nil. Undecodable entries are
+// taken as missing.
+func (c *CachingFetcher) get(ctx context.Context, url string) *cacheEntry {
+	data, err := c.Cache.Get(ctx, url)
+	if err != nil {
+		if err != ErrCacheMiss {
+			c.report(err)
+		}
+		return nil
+	}
+	var entry cacheEntry
+	if json.Unmarshal(data, &entry) != nil {
+		return nil
+	}
+	return &entry
+}
+
+// fetch fetches the document at url, revalidating the stale entry if any,
+// and caches the resulting entry.
+func (c *CachingFetcher) fetch(ctx context.Context, url string, stale *cacheEntry) (*cacheEntry, error) {
+	header := make(http.Header)
+	if stale != nil && stale.ETag != "" {
+		header.Set("If-None-Match", stale.ETag)
+	}
+	if stale != nil && stale.LastModified != "" {
+		header.Set("If-Modified-Since", stale.LastModified)
+	}
+	f := c.Fetcher
+	if f == nil {
+		f = &defaultFetcher
+	}
+	doc, err := f.fetch(ctx, url, header)
+	if err != nil && (err != ErrNoProperties || doc == nil) {
+		return nil, err
+	}
+	var entry cacheEntry
+	if doc.notModified {
+		entry = *stale
+	} else {
+		entry = cacheEntry{URL: doc.url}
+		if doc.object != nil {
+			entry.Properties = doc.object.Properties()
+			entry.Namespaces = doc.data.Namespaces
+			entry.Sources = doc.data.Sources
+			entry.TTL = doc.data.TTL
+		}
+	}
+	if etag := doc.header.Get("ETag"); etag != "" || !doc.notModified {
+		entry.ETag = etag
+	}
+	if lastModified := doc.header.Get("Last-Modified"); lastModified != "" || !doc.notModified {
+		entry.LastModified = lastModified
+	}
+	ttl, store := c.lifetime(doc.header, entry.TTL)
+	if !store {
+		c.report(c.Cache.Delete(ctx, url))
+		return &entry, nil
+	}
+	entry.Expires = c.now().Add(ttl)
+	data, err := json.Marshal(&entry)
+	if err == nil {
+		err = c.Cache.Put(ctx, url, data)
+	}
+	c.report(err)
+	return &entry, nil
+}
+
+// lifetime returns the lifetime of an object given the header of its
+// document and its `og:ttl`, and reports whether it may be stored at all.
+// Responses marked no-store or private aren't stored, and no-cache ones must
+// be revalidated. Otherwise the lifetime is the shortest of the `og:ttl` and
+// the one given by Cache-Control or Expires.
+func (c *CachingFetcher) lifetime(header http.Header, ttl time.Duration) (time.Duration, bool) {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg := directive, ""
+			if index := strings.IndexByte(directive, '='); index >= 0 {
+				name, arg = directive[:index], strings.Trim(strings.TrimSpace(directive[index+1:]), `"`)
+			}
+			directives[strings.ToLower(strings.TrimSpace(name))] = arg
+		}
+	}
+	_, noStore := directives["no-store"]
+	_, private := directives["private"]
+	if noStore || private {
+		// The cache is shared between the users of the fetcher.
+		return 0, false
+	}
+	if _, ok := directives["no-cache"]; ok {
+		return 0, true
+	}
+	age, _ := strconv.ParseInt(header.Get("Age"), 10, 64)
+	lifetime, ok := time.Duration(0), true
+	switch {
+	case directives["s-maxage"] != "" || directives["max-age"] != "":
+		maxAge := directives["s-maxage"]
+		if maxAge == "" {
+			maxAge = directives["max-age"]
+		}
+		seconds, _ := strconv.ParseInt(maxAge, 10, 64)
+		lifetime = time.Duration(seconds-age) * time.Second
+	case header.Get("Expires") != "":
+		// An invalid Expires header means the document is already expired.
+		if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+			date, err := http.ParseTime(header.Get("Date"))
+			if err != nil {
+				date = c.now()
+			}
+			lifetime = expires.Sub(date) - time.Duration(age)*time.Second
+		}
+	default:
+		ok = false
+	}
+	switch {
+	case ttl > 0 && (!ok || ttl < lifetime):
+		lifetime = ttl
+	case !ok:
+		lifetime = c.DefaultTTL
+		if lifetime == 0 {
+			lifetime = DefaultCacheTTL
+		}
+	}
+	if c.MaxTTL > 0 && lifetime > c.MaxTTL {
+		lifetime = c.MaxTTL
+	}
+	if lifetime < 0 {
+		lifetime = 0
+	}
+	return lifetime, true
+}
+
+// report passes err to OnError, if both are not nil.
+func (c *CachingFetcher) report(err error) {
+	if err != nil && c.OnError != nil {
+		c.OnError(err)
+	}
+}
+
+func (c *CachingFetcher) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
+
+// MemoryCache is a Cache held in memory, which evicts the least recently
+// used entries once full.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    *list.List
+	index      map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key  string
+	data []byte
+}
+
+// NewMemoryCache returns a cache holding up to maxEntries entries, or an
+// unlimited number of entries if maxEntries is zero.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: maxEntries, entries: list.New(), index: make(map[string]*list.Element)}
+}
+
+// Get returns the data stored for key, or ErrCacheMiss.
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.index[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	c.entries.MoveToFront(e)
+	return e.Value.(*memoryEntry).data, nil
+}
+
+// Put stores data for key, evicting the least recently used entry if the
+// cache is full.
+func (c *MemoryCache) Put(ctx context.Context, key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.index[key]; ok {
+		e.Value.(*memoryEntry).data = data
+		c.entries.MoveToFront(e)
+		return nil
+	}
+	c.index[key] = c.entries.PushFront(&memoryEntry{key: key, data: data})
+	if c.maxEntries > 0 && c.entries.Len() > c.maxEntries {
+		e := c.entries.Back()
+		c.entries.Remove(e)
+		delete(c.index, e.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Delete removes the data stored for key, if any.
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.index[key]; ok {
+		c.entries.Remove(e)
+		delete(c.index, key)
+	}
+	return nil
+}
+
+// Len returns the number of entries held by the cache.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// DirCache is a Cache storing its entries as files in a directory, created
+// if needed. The files are named after the hash of their key, and are never
+// evicted.
+type DirCache string
+
+// Get returns the data stored for key, or ErrCacheMiss.
+func (d DirCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(d.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrCacheMiss
+	}
+	return data, err
+}
+
+// Put stores data for key. The file is written atomically, so that
+// concurrent readers never see a partial entry.
+func (d DirCache) Put(ctx context.Context, key string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(string(d), 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(string(d), ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Delete removes the data stored for key, if any.
+func (d DirCache) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.Remove(d.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (d DirCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(string(d), hex.EncodeToString(sum[:]))
+}
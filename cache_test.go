@@ -0,0 +1,393 @@
+package ogp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+// clock is a fake clock, moved forward by the tests.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// origin serves documents whose headers are set by the tests, and counts the
+// requests it gets.
+type origin struct {
+	*httptest.Server
+	requests  int32
+	modified  int32
+	headers   map[string]http.Header
+	documents map[string]string
+	release   chan struct{}
+}
+
+func newOrigin() *origin {
+	o := &origin{headers: make(map[string]http.Header), documents: make(map[string]string)}
+	o.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&o.requests, 1)
+		if o.release != nil {
+			<-o.release
+		}
+		for name, values := range o.headers[r.URL.Path] {
+			w.Header()[name] = values
+		}
+		etag := w.Header().Get("ETag")
+		if etag != "" && r.Header.Get("If-None-Match") == etag ||
+			r.Header.Get("If-Modified-Since") != "" && r.Header.Get("If-Modified-Since") == w.Header().Get("Last-Modified") {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&o.modified, 1)
+		fmt.Fprint(w, o.documents[r.URL.Path])
+	}))
+	return o
+}
+
+func (o *origin) serve(path, title string, header ...string) {
+	h := make(http.Header)
+	for i := 0; i+1 < len(header); i += 2 {
+		h.Add(header[i], header[i+1])
+	}
+	o.headers[path] = h
+	o.documents[path] = `<html><head><meta property="og:title" content="` + title + `"></head></html>`
+}
+
+func (o *origin) counts() (int, int) {
+	return int(atomic.LoadInt32(&o.requests)), int(atomic.LoadInt32(&o.modified))
+}
+
+const ttlDocument = `<html><head><meta property="og:title" content="TTL"><meta property="og:ttl" content="3600"></head></html>`
+
+func TestCachingFetcher(t *testing.T) {
+	o := newOrigin()
+	defer o.Close()
+	o.serve("/max-age", "Max age", "Cache-Control", "public, max-age=60", "ETag", `"v1"`)
+	o.serve("/age", "Age", "Cache-Control", "max-age=60", "Age", "50")
+	o.serve("/ttl", "TTL", "Cache-Control", "max-age=86400")
+	o.documents["/ttl"] = ttlDocument
+	o.serve("/max-age-ttl", "TTL", "Cache-Control", "max-age=60")
+	o.documents["/max-age-ttl"] = ttlDocument
+	o.serve("/no-store-ttl", "TTL", "Cache-Control", "no-store")
+	o.documents["/no-store-ttl"] = ttlDocument
+	o.serve("/private", "Private", "Cache-Control", "private, max-age=60")
+	o.serve("/expires", "Expires", "Date", "Mon, 02 Jan 2006 15:04:05 GMT", "Expires", "Mon, 02 Jan 2006 15:34:05 GMT",
+		"Last-Modified", "Mon, 02 Jan 2006 12:00:00 GMT")
+	o.serve("/no-store", "No store", "Cache-Control", "no-store")
+	o.serve("/no-cache", "No cache", "Cache-Control", "no-cache", "ETag", `"v1"`)
+	o.serve("/default", "Default")
+	o.documents["/none"] = `<html><head><title>None</title></head></html>`
+	tests := []struct {
+		path     string
+		title    string
+		after    time.Duration
+		requests int
+		modified int
+	}{
+		{"/max-age", "Max age", 59 * time.Second, 1, 1},
+		{"/max-age", "Max age", 61 * time.Second, 2, 1},
+		{"/age", "Age", 9 * time.Second, 1, 1},
+		{"/age", "Age", 11 * time.Second, 2, 2},
+		{"/ttl", "TTL", 59 * time.Minute, 1, 1},
+		{"/ttl", "TTL", 61 * time.Minute, 2, 2},
+		{"/max-age-ttl", "TTL", 59 * time.Second, 1, 1},
+		{"/max-age-ttl", "TTL", 61 * time.Second, 2, 2},
+		{"/no-store-ttl", "TTL", 0, 2, 2},
+		{"/private", "Private", 0, 2, 2},
+		{"/expires", "Expires", 29 * time.Minute, 1, 1},
+		{"/expires", "Expires", 31 * time.Minute, 2, 1},
+		{"/no-store", "No store", 0, 2, 2},
+		{"/no-cache", "No cache", 0, 2, 1},
+		{"/default", "Default", 59 * time.Minute, 1, 1},
+		{"/default", "Default", 61 * time.Minute, 2, 2},
+		{"/none", "", time.Minute, 1, 1},
+	}
+	for _, test := range tests {
+		atomic.StoreInt32(&o.requests, 0)
+		atomic.StoreInt32(&o.modified, 0)
+		clock := &clock{now: time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)}
+		c := &ogp.CachingFetcher{
+			Fetcher: &ogp.Fetcher{Policy: local(o.Server)},
+			Cache:   ogp.NewMemoryCache(0),
+			Now:     clock.Now,
+		}
+		for i := 0; i < 2; i++ {
+			object, url, err := c.Fetch(context.Background(), o.URL+test.path)
+			if url != o.URL+test.path {
+				t.Errorf("%s: unexpected URL: %s", test.path, url)
+			}
+			if test.title == "" {
+				if err != ogp.ErrNoProperties {
+					t.Errorf("%s: unexpected error: %v", test.path, err)
+				}
+			} else if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.path, err)
+			} else if result := title(t, object); result != test.title {
+				t.Errorf("%s: unexpected title: %s", test.path, result)
+			}
+			clock.Advance(test.after)
+		}
+		if requests, modified := o.counts(); requests != test.requests || modified != test.modified {
+			t.Errorf("%s after %v: unexpected requests: %d, %d", test.path, test.after, requests, modified)
+		}
+	}
+}
+
+func TestCachingFetcherRevalidation(t *testing.T) {
+	o := newOrigin()
+	defer o.Close()
+	o.serve("/", "First", "Cache-Control", "max-age=60", "ETag", `"v1"`)
+	clock := &clock{now: time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)}
+	c := &ogp.CachingFetcher{
+		Fetcher: &ogp.Fetcher{Policy: local(o.Server)},
+		Cache:   ogp.DirCache(t.TempDir()),
+		Now:     clock.Now,
+	}
+	fetch := func(expected string) {
+		t.Helper()
+		object, _, err := c.Fetch(context.Background(), o.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result := title(t, object); result != expected {
+			t.Errorf("unexpected title: %s", result)
+		}
+	}
+	fetch("First")
+	clock.Advance(2 * time.Minute)
+	fetch("First")
+	if requests, modified := o.counts(); requests != 2 || modified != 1 {
+		t.Errorf("unexpected requests: %d, %d", requests, modified)
+	}
+	// The revalidated entry is fresh again.
+	clock.Advance(30 * time.Second)
+	fetch("First")
+	if requests, _ := o.counts(); requests != 2 {
+		t.Errorf("unexpected requests: %d", requests)
+	}
+	o.serve("/", "Second", "Cache-Control", "max-age=60", "ETag", `"v2"`)
+	clock.Advance(time.Minute)
+	fetch("Second")
+	if requests, modified := o.counts(); requests != 3 || modified != 2 {
+		t.Errorf("unexpected requests: %d, %d", requests, modified)
+	}
+}
+
+func TestCachingFetcherConcurrency(t *testing.T) {
+	o := newOrigin()
+	defer o.Close()
+	o.serve("/", "Popular", "Cache-Control", "max-age=60")
+	o.release = make(chan struct{})
+	c := &ogp.CachingFetcher{
+		Fetcher: &ogp.Fetcher{Policy: local(o.Server)},
+		Cache:   ogp.NewMemoryCache(0),
+		Now:     (&clock{now: time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)}).Now,
+	}
+	var wg sync.WaitGroup
+	titles := make([]string, 10)
+	for i := range titles {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if object, _, err := c.Fetch(context.Background(), o.URL); err == nil {
+				titles[i] = object.(*ogp.WebsiteBuilder).Data().Title
+			}
+		}(i)
+	}
+	close(o.release)
+	wg.Wait()
+	for i, result := range titles {
+		if result != "Popular" {
+			t.Errorf("unexpected title %d: %q", i, result)
+		}
+	}
+	if requests, _ := o.counts(); requests != 1 {
+		t.Errorf("unexpected requests: %d", requests)
+	}
+}
+
+// signalingCache signals the Get calls of the CachingFetcher, and panics on
+// Put if told so.
+type signalingCache struct {
+	ogp.Cache
+	gets  chan struct{}
+	panic bool
+}
+
+func (c *signalingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if c.gets != nil {
+		c.gets <- struct{}{}
+	}
+	return c.Cache.Get(ctx, key)
+}
+
+func (c *signalingCache) Put(ctx context.Context, key string, data []byte) error {
+	if c.panic {
+		panic("put")
+	}
+	return c.Cache.Put(ctx, key, data)
+}
+
+func TestCachingFetcherCancel(t *testing.T) {
+	o := newOrigin()
+	defer o.Close()
+	o.serve("/", "Popular", "Cache-Control", "max-age=60")
+	o.release = make(chan struct{})
+	cache := &signalingCache{Cache: ogp.NewMemoryCache(0), gets: make(chan struct{})}
+	c := &ogp.CachingFetcher{
+		Fetcher: &ogp.Fetcher{Policy: local(o.Server)},
+		Cache:   cache,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, _, err := c.Fetch(ctx, o.URL)
+		canceled <- err
+	}()
+	<-cache.gets
+	for requests, _ := o.counts(); requests == 0; requests, _ = o.counts() {
+		time.Sleep(time.Millisecond)
+	}
+	done := make(chan string)
+	go func() {
+		object, _, err := c.Fetch(context.Background(), o.URL)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			done <- ""
+			return
+		}
+		done <- title(t, object)
+	}()
+	<-cache.gets
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-canceled; err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+	close(o.release)
+	if result := <-done; result != "Popular" {
+		t.Errorf("unexpected title: %q", result)
+	}
+}
+
+func TestCachingFetcherPanic(t *testing.T) {
+	o := newOrigin()
+	defer o.Close()
+	o.serve("/", "Popular", "Cache-Control", "max-age=60")
+	c := &ogp.CachingFetcher{
+		Fetcher: &ogp.Fetcher{Policy: local(o.Server)},
+		Cache:   &signalingCache{Cache: ogp.NewMemoryCache(0), panic: true},
+	}
+	for i := 0; i < 2; i++ {
+		func() {
+			defer func() {
+				if recover() != "put" {
+					t.Error("fetch didn't panic")
+				}
+			}()
+			c.Fetch(context.Background(), o.URL)
+		}()
+	}
+}
+
+// failingCache fails every call with the operation it was called for.
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, errors.New("get")
+}
+
+func (failingCache) Put(ctx context.Context, key string, data []byte) error {
+	return errors.New("put")
+}
+
+func (failingCache) Delete(ctx context.Context, key string) error {
+	return errors.New("delete")
+}
+
+func TestCachingFetcherErrors(t *testing.T) {
+	o := newOrigin()
+	defer o.Close()
+	o.serve("/", "Popular", "Cache-Control", "max-age=60")
+	o.serve("/no-store", "No store", "Cache-Control", "no-store")
+	var errs []string
+	c := &ogp.CachingFetcher{
+		Fetcher: &ogp.Fetcher{Policy: local(o.Server)},
+		Cache:   failingCache{},
+		OnError: func(err error) { errs = append(errs, err.Error()) },
+	}
+	for _, path := range []string{"/", "/no-store"} {
+		if _, _, err := c.Fetch(context.Background(), o.URL+path); err != nil {
+			t.Errorf("%s: unexpected error: %v", path, err)
+		}
+	}
+	if expected := []string{"get", "put", "get", "delete"}; !reflect.DeepEqual(errs, expected) {
+		t.Errorf("unexpected errors: %v, expected: %v", errs, expected)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := ogp.NewMemoryCache(2)
+	c.Put(ctx, "a", []byte("1"))
+	c.Put(ctx, "b", []byte("2"))
+	c.Get(ctx, "a")
+	c.Put(ctx, "c", []byte("3"))
+	if _, err := c.Get(ctx, "b"); err != ogp.ErrCacheMiss {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for key, expected := range map[string]string{"a": "1", "c": "3"} {
+		if data, err := c.Get(ctx, key); err != nil || string(data) != expected {
+			t.Errorf("unexpected data for %s: %q, %v", key, data, err)
+		}
+	}
+	c.Delete(ctx, "a")
+	if _, err := c.Get(ctx, "a"); err != ogp.ErrCacheMiss || c.Len() != 1 {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDirCache(t *testing.T) {
+	ctx := context.Background()
+	c := ogp.DirCache(t.TempDir() + "/cache")
+	if _, err := c.Get(ctx, "http://example.com/"); err != ogp.ErrCacheMiss {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := c.Put(ctx, "http://example.com/", []byte("data")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := c.Get(ctx, "http://example.com/"); err != nil || string(data) != "data" {
+		t.Errorf("unexpected data: %q, %v", data, err)
+	}
+	if err := c.Delete(ctx, "http://example.com/"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := c.Get(ctx, "http://example.com/"); err != ogp.ErrCacheMiss {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := c.Delete(ctx, "http://example.com/"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package ogp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// CharsetReader returns a reader decoding input from the given charset to
+// UTF-8. It has the signature of NewReaderLabel in
+// golang.org/x/net/html/charset, which supports every charset of the
+// WHATWG Encoding Standard.
+type CharsetReader func(charset string, input io.Reader) (io.Reader, error)
+
+// prescanSize is the number of bytes looked at for a `<meta>` declaring the
+// charset, as the HTML specification suggests.
+const prescanSize = 1024
+
+// decodeCharset returns a reader decoding the HTML document read from r to
+// UTF-8. The charset comes from the byte order mark of the document, the
+// Content-Type header or a `<meta>` element, in that order, and defaults to
+// UTF-8. The charsets that aren't built in are handled by charsetReader.
+func decodeCharset(r *bufio.Reader, contentType string, charsetReader CharsetReader) (io.Reader, error) {
+	peek, _ := r.Peek(3)
+	switch {
+	case bytes.HasPrefix(peek, []byte{0xef, 0xbb, 0xbf}):
+		r.Discard(3)
+		return r, nil
+	case bytes.HasPrefix(peek, []byte{0xff, 0xfe}):
+		r.Discard(2)
+		return newCharsetReader(r, decodeUTF16LE), nil
+	case bytes.HasPrefix(peek, []byte{0xfe, 0xff}):
+		r.Discard(2)
+		return newCharsetReader(r, decodeUTF16BE), nil
+	}
+	var charset string
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		charset = params["charset"]
+	}
+	if charset == "" {
+		peek, _ := r.Peek(prescanSize)
+		charset = metaCharset(peek)
+	}
+	switch normalizeCharset(charset) {
+	case "", "utf-8":
+		return r, nil
+	case "windows-1252":
+		return newCharsetReader(r, decodeWindows1252), nil
+	case "utf-16le":
+		return newCharsetReader(r, decodeUTF16LE), nil
+	case "utf-16be":
+		return newCharsetReader(r, decodeUTF16BE), nil
+	}
+	if charsetReader == nil {
+		return nil, fmt.Errorf("ogp: unsupported charset %q", charset)
+	}
+	return charsetReader(charset, r)
+}
+
+// metaCharset returns the charset declared by the `<meta charset>` or
+// `<meta http-equiv="Content-Type">` element found in the beginning of a
+// document.
+func metaCharset(b []byte) string {
+	z := newTokenizer(bytes.NewReader(b))
+	for {
+		t, err := z.next()
+		if err != nil {
+			return ""
+		}
+		if t.typ != startTagToken || t.name != "meta" {
+			continue
+		}
+		charset, ok := t.attr("charset")
+		if !ok {
+			if equiv, _ := t.attr("http-equiv"); strings.EqualFold(equiv, "content-type") {
+				content, _ := t.attr("content")
+				_, params, _ := mime.ParseMediaType(content)
+				charset = params["charset"]
+			}
+		}
+		if charset = strings.TrimSpace(charset); charset == "" {
+			continue
+		}
+		// A document readable as ASCII to find the declaration can't be
+		// UTF-16, as the HTML specification notes.
+		if strings.HasPrefix(normalizeCharset(charset), "utf-16") {
+			return "utf-8"
+		}
+		return charset
+	}
+}
+
+// normalizeCharset returns the name of the built-in decoder of charset. As
+// in the WHATWG Encoding Standard, ASCII and ISO-8859-1 are decoded as
+// windows-1252, which is a superset of both.
+func normalizeCharset(charset string) string {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "":
+		return ""
+	case "utf-8", "utf8", "unicode-1-1-utf-8":
+		return "utf-8"
+	case "us-ascii", "ascii", "iso-8859-1", "iso8859-1", "iso_8859-1", "latin1", "l1", "windows-1252", "cp1252", "x-cp1252":
+		return "windows-1252"
+	case "utf-16", "utf-16le":
+		return "utf-16le"
+	case "utf-16be":
+		return "utf-16be"
+	}
+	return charset
+}
+
+// charsetReader decodes the bytes read from r to UTF-8 with decode, which
+// writes the decoded runes to out and returns the number of bytes of src
+// consumed, keeping incomplete sequences for the next call.
+type charsetReader struct {
+	r      io.Reader
+	decode func(out *bytes.Buffer, src []byte) int
+	in     []byte
+	out    bytes.Buffer
+	err    error
+}
+
+func newCharsetReader(r io.Reader, decode func(out *bytes.Buffer, src []byte) int) *charsetReader {
+	return &charsetReader{r: r, decode: decode}
+}
+
+func (c *charsetReader) Read(p []byte) (int, error) {
+	var buf [4096]byte
+	for c.out.Len() == 0 && c.err == nil {
+		n, err := c.r.Read(buf[:])
+		c.in = append(c.in, buf[:n]...)
+		c.in = c.in[:copy(c.in, c.in[c.decode(&c.out, c.in):])]
+		if err != nil {
+			if len(c.in) > 0 {
+				c.out.WriteRune(utf8.RuneError)
+			}
+			c.err = err
+		}
+	}
+	if c.out.Len() > 0 {
+		return c.out.Read(p)
+	}
+	return 0, c.err
+}
+
+// windows1252 maps the bytes 0x80 to 0x9f of windows-1252 to their runes.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+func decodeWindows1252(out *bytes.Buffer, src []byte) int {
+	for _, c := range src {
+		switch {
+		case c < 0x80:
+			out.WriteByte(c)
+		case c < 0xa0:
+			out.WriteRune(windows1252[c-0x80])
+		default:
+			out.WriteRune(rune(c))
+		}
+	}
+	return len(src)
+}
+
+func decodeUTF16LE(out *bytes.Buffer, src []byte) int {
+	return decodeUTF16(out, src, func(b []byte) uint16 { return uint16(b[0]) | uint16(b[1])<<8 })
+}
+
+func decodeUTF16BE(out *bytes.Buffer, src []byte) int {
+	return decodeUTF16(out, src, func(b []byte) uint16 { return uint16(b[0])<<8 | uint16(b[1]) })
+}
+
+func decodeUTF16(out *bytes.Buffer, src []byte, unit func([]byte) uint16) int {
+	i := 0
+	for ; i+1 < len(src); i += 2 {
+		r := rune(unit(src[i:]))
+		if utf16.IsSurrogate(r) {
+			if i+3 >= len(src) {
+				break
+			}
+			if r2 := rune(unit(src[i+2:])); r < 0xdc00 && r2 >= 0xdc00 && r2 <= 0xdfff {
+				out.WriteRune(utf16.DecodeRune(r, r2))
+				i += 2
+				continue
+			}
+			r = utf8.RuneError
+		}
+		out.WriteRune(r)
+	}
+	return i
+}
@@ -0,0 +1,284 @@
+package ogp
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateTime is a `DateTime` value of the specification, which is either a
+// calendar date, rendered as `2006-01-02`, or an instant rendered along with
+// its UTC offset, e.g. `2006-01-02T15:04:05-07:00`. Unlike a time.Time, a
+// date doesn't shift to the previous or next day depending on the time zone
+// of the reader.
+type DateTime struct {
+	t    time.Time
+	date bool
+}
+
+// NewDate returns the calendar date of the given year, month and day.
+func NewDate(year int, month time.Month, day int) DateTime {
+	return DateTime{t: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), date: true}
+}
+
+// DateOf returns the calendar date of t in its location.
+func DateOf(t time.Time) DateTime {
+	return NewDate(t.Date())
+}
+
+// TimeOf returns the instant t, rendered with the UTC offset of its location.
+func TimeOf(t time.Time) DateTime {
+	return DateTime{t: t}
+}
+
+var errDateTimeSyntax = errors.New("ogp: invalid ISO 8601 date or time syntax")
+
+// ParseDateTime parses an ISO 8601 calendar date, ordinal date or week date,
+// in extended or basic format, e.g. `2006-01-02`, `2006-002` or `2006-W01-1`,
+// or a date of reduced precision, `2006` or `2006-01`, which is taken as the
+// first day of the year or month. A complete date may be followed by a time
+// of day at hour, minute or second precision with an optional decimal
+// fraction of a second, e.g. `T15`, `T15:04` or `T150405.999`, and a UTC
+// offset, e.g. `Z`, `-07`, `-07:00` or `-0700`. A time without an offset is
+// taken as UTC, and a date without a time is a calendar date.
+func ParseDateTime(s string) (DateTime, error) {
+	index := strings.IndexAny(s, "Tt ")
+	if index < 0 {
+		date, err := parseDate(s, true)
+		if err != nil {
+			return DateTime{}, err
+		}
+		return DateTime{t: date, date: true}, nil
+	}
+	date, err := parseDate(s[:index], false)
+	if err != nil {
+		return DateTime{}, err
+	}
+	clock, zone := s[index+1:], ""
+	if index := strings.IndexAny(clock, "Zz+-"); index >= 0 {
+		clock, zone = clock[:index], clock[index:]
+	}
+	loc, err := parseZone(zone)
+	if err != nil {
+		return DateTime{}, err
+	}
+	hour, minute, second, nsec, err := parseClock(clock)
+	if err != nil {
+		return DateTime{}, err
+	}
+	year, month, day := date.Date()
+	return DateTime{t: time.Date(year, month, day, hour, minute, second, nsec, loc)}, nil
+}
+
+// MustParseDateTime is like ParseDateTime but panics if s is not a valid
+// date or time. It eases the use of constant dates.
+func MustParseDateTime(s string) DateTime {
+	d, err := ParseDateTime(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Time returns the instant of d, or midnight UTC for a calendar date.
+func (d DateTime) Time() time.Time {
+	return d.t
+}
+
+// IsDate reports whether d is a calendar date.
+func (d DateTime) IsDate() bool {
+	return d.date
+}
+
+// IsZero reports whether d is the zero value.
+func (d DateTime) IsZero() bool {
+	return d.t.IsZero()
+}
+
+func (d DateTime) String() string {
+	if d.date {
+		return d.t.Format("2006-01-02")
+	}
+	return d.t.Format(time.RFC3339Nano)
+}
+
+// MarshalText encodes d as its string representation, so that a calendar
+// date remains a date in JSON.
+func (d DateTime) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a date or time encoded by MarshalText.
+func (d *DateTime) UnmarshalText(text []byte) error {
+	dateTime, err := ParseDateTime(string(text))
+	if err != nil {
+		return err
+	}
+	*d = dateTime
+	return nil
+}
+
+// parseDate parses an ISO 8601 calendar, ordinal or week date as midnight
+// UTC. With reduced, a year or a year and month in extended format is
+// accepted too.
+func parseDate(s string, reduced bool) (time.Time, error) {
+	extended := len(s) > 4 && s[4] == '-'
+	rest := s
+	if extended {
+		rest = s[5:]
+	} else if len(s) > 4 {
+		rest = s[4:]
+	}
+	year, ok := atoi(s[:minInt(len(s), 4)], 4)
+	if !ok {
+		return time.Time{}, errDateTimeSyntax
+	}
+	switch {
+	case reduced && len(s) == 4:
+		// Year, e.g. 2006.
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), nil
+	case reduced && extended && len(rest) == 2:
+		// Year and month, e.g. 2006-01. The basic format isn't allowed.
+		month, ok := atoi(rest, 2)
+		if !ok || month < 1 || month > 12 {
+			return time.Time{}, errDateTimeSyntax
+		}
+		return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC), nil
+	case len(rest) > 0 && rest[0] == 'W':
+		// Week date, e.g. 2006-W01-1 or 2006W011.
+		rest = rest[1:]
+		if extended {
+			if len(rest) != 4 || rest[2] != '-' {
+				return time.Time{}, errDateTimeSyntax
+			}
+			rest = rest[:2] + rest[3:]
+		}
+		week, ok := atoi(rest[:minInt(len(rest), 2)], 2)
+		day, ok2 := atoi(rest[minInt(len(rest), 2):], 1)
+		if !ok || !ok2 || week < 1 || day < 1 || day > 7 {
+			return time.Time{}, errDateTimeSyntax
+		}
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+		offset := int(jan4.Weekday()+6) % 7
+		t := jan4.AddDate(0, 0, (week-1)*7+day-1-offset)
+		if y, w := t.ISOWeek(); y != year || w != week {
+			return time.Time{}, errDateTimeSyntax
+		}
+		return t, nil
+	case len(rest) == 3:
+		// Ordinal date, e.g. 2006-002 or 2006002.
+		day, ok := atoi(rest, 3)
+		t := time.Date(year, time.January, day, 0, 0, 0, 0, time.UTC)
+		if !ok || day < 1 || t.Year() != year {
+			return time.Time{}, errDateTimeSyntax
+		}
+		return t, nil
+	default:
+		// Calendar date, e.g. 2006-01-02 or 20060102.
+		if extended {
+			if len(rest) != 5 || rest[2] != '-' {
+				return time.Time{}, errDateTimeSyntax
+			}
+			rest = rest[:2] + rest[3:]
+		}
+		month, ok := atoi(rest[:minInt(len(rest), 2)], 2)
+		day, ok2 := atoi(rest[minInt(len(rest), 2):], 2)
+		t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if !ok || !ok2 || month < 1 || month > 12 || t.Month() != time.Month(month) || t.Day() != day {
+			return time.Time{}, errDateTimeSyntax
+		}
+		return t, nil
+	}
+}
+
+// parseClock parses an ISO 8601 time of day without its offset, e.g. 15,
+// 15:04, 15:04:05.999 or 150405. 24:00 is midnight at the end of the day.
+func parseClock(s string) (hour, minute, second, nsec int, err error) {
+	fraction := ""
+	if index := strings.IndexAny(s, ".,"); index >= 0 {
+		s, fraction = s[:index], s[index+1:]
+	}
+	parts := []string{s}
+	if strings.IndexByte(s, ':') >= 0 {
+		parts = strings.Split(s, ":")
+	} else if len(s) > 2 {
+		parts = nil
+		for i := 0; i < len(s); i += 2 {
+			parts = append(parts, s[i:minInt(len(s), i+2)])
+		}
+	}
+	if len(parts) > 3 || fraction != "" && len(parts) != 3 {
+		return 0, 0, 0, 0, errDateTimeSyntax
+	}
+	values := make([]int, 3)
+	for i, part := range parts {
+		value, ok := atoi(part, 2)
+		if !ok {
+			return 0, 0, 0, 0, errDateTimeSyntax
+		}
+		values[i] = value
+	}
+	if fraction != "" {
+		if len(fraction) > 9 {
+			fraction = fraction[:9]
+		}
+		nsec, err = strconv.Atoi(fraction + strings.Repeat("0", 9-len(fraction)))
+		if err != nil || strings.IndexFunc(fraction, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+			return 0, 0, 0, 0, errDateTimeSyntax
+		}
+	}
+	hour, minute, second = values[0], values[1], values[2]
+	if hour == 24 && (minute != 0 || second != 0 || nsec != 0) || hour > 24 || minute > 59 || second > 59 {
+		return 0, 0, 0, 0, errDateTimeSyntax
+	}
+	return hour, minute, second, nsec, nil
+}
+
+// parseZone parses an ISO 8601 UTC offset, e.g. Z, -07, -07:00 or -0700,
+// defaulting to UTC.
+func parseZone(s string) (*time.Location, error) {
+	if s == "" || s == "Z" || s == "z" {
+		return time.UTC, nil
+	}
+	sign := 1
+	if s[0] == '-' {
+		sign = -1
+	}
+	s = strings.Replace(s[1:], ":", "", 1)
+	hours, ok := atoi(s[:minInt(len(s), 2)], 2)
+	minutes, ok2 := 0, true
+	if len(s) > 2 {
+		minutes, ok2 = atoi(s[2:], 2)
+	}
+	if !ok || !ok2 || hours > 23 || minutes > 59 {
+		return nil, errDateTimeSyntax
+	}
+	offset := sign * (hours*3600 + minutes*60)
+	if offset == 0 {
+		return time.UTC, nil
+	}
+	return time.FixedZone("", offset), nil
+}
+
+// atoi parses exactly n decimal digits.
+func atoi(s string, n int) (int, bool) {
+	if len(s) != n {
+		return 0, false
+	}
+	result := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		result = result*10 + int(s[i]-'0')
+	}
+	return result, true
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,86 @@
+package ogp_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestReleaseDate(t *testing.T) {
+	paris := time.FixedZone("", 2*3600)
+	newYork := time.FixedZone("", -4*3600)
+	tests := []struct {
+		releaseDate ogp.DateTime
+		expected    string
+	}{
+		{ogp.NewDate(2020, time.May, 1), "2020-05-01"},
+		{ogp.DateOf(time.Date(2020, time.May, 1, 23, 30, 0, 0, newYork)), "2020-05-01"},
+		{ogp.TimeOf(time.Date(2020, time.May, 1, 10, 30, 0, 0, paris)), "2020-05-01T10:30:00+02:00"},
+		{ogp.TimeOf(time.Date(2020, time.May, 1, 10, 30, 0, 0, time.UTC)), "2020-05-01T10:30:00Z"},
+	}
+	for _, test := range tests {
+		props := ogp.Book().URL("http://example.com/book").ReleaseDate(test.releaseDate).Properties()
+		if result := props[len(props)-1]; result.Name != "book:release_date" || result.Content != test.expected {
+			t.Errorf("%s: unexpected property: %v", test.expected, result)
+		}
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	date := time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		text     string
+		expected time.Time
+		isDate   bool
+	}{
+		{"2020-05-01", date, true},
+		{"20200501", date, true},
+		{"2020-122", date, true},
+		{"2020122", date, true},
+		{"2020-W18-5", date, true},
+		{"2020W185", date, true},
+		{"2020", time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2020-05", time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2020-05-01T10", date.Add(10 * time.Hour), false},
+		{"2020-05-01T10:30", date.Add(10*time.Hour + 30*time.Minute), false},
+		{"2020-05-01T10:30:15Z", date.Add(10*time.Hour + 30*time.Minute + 15*time.Second), false},
+		{"20200501T103015,5Z", date.Add(10*time.Hour + 30*time.Minute + 15500*time.Millisecond), false},
+		{"2020-05-01T10:30:15.123456789+02:00", date.Add(8*time.Hour + 30*time.Minute + 15123456789), false},
+		{"2020-05-01T10:30-0230", date.Add(13 * time.Hour), false},
+		{"2020-05-01T10:30-02", date.Add(12*time.Hour + 30*time.Minute), false},
+		{"2020-05-01T24:00Z", date.AddDate(0, 0, 1), false},
+	}
+	for _, test := range tests {
+		result, err := ogp.ParseDateTime(test.text)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.text, err)
+			continue
+		}
+		if !result.Time().Equal(test.expected) || result.IsDate() != test.isDate {
+			t.Errorf("%s: unexpected result: %v", test.text, result)
+		}
+	}
+	for _, text := range []string{"", "202", "2020-13", "2020-5", "202005", "2020T10", "2020-05T10", "2020-13-01", "2019-02-29", "2019-366", "2020-W54-1", "2020-W18-8",
+		"2020-05-01T", "2020-05-01T25:00", "2020-05-01T24:30", "2020-05-01T10:30.5", "2020-05-01T10:30+2", "2020-05-01T10:30:15Zulu"} {
+		if result, err := ogp.ParseDateTime(text); err == nil {
+			t.Errorf("%s: unexpected result: %v", text, result)
+		}
+	}
+}
+
+func TestDateTimeJSON(t *testing.T) {
+	data := ogp.Album().ReleaseDate(ogp.NewDate(2020, time.May, 1)).Data()
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded ogp.MusicAlbumData
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.ReleaseDate == nil || decoded.ReleaseDate.String() != "2020-05-01" || !decoded.ReleaseDate.IsDate() {
+		t.Errorf("unexpected release date: %v", decoded.ReleaseDate)
+	}
+}
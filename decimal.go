@@ -0,0 +1,103 @@
+package ogp
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, such as a price amount. Unlike a
+// float64, it is rendered exactly as it was given, e.g. `19.90` stays
+// `19.90`.
+type Decimal struct {
+	unscaled int64
+	scale    int
+}
+
+// NewDecimal returns the decimal unscaled × 10^-scale, e.g. NewDecimal(1990, 2)
+// for 19.90.
+func NewDecimal(unscaled int64, scale int) Decimal {
+	for ; scale < 0; scale++ {
+		unscaled *= 10
+	}
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+var errDecimalSyntax = errors.New("ogp: invalid decimal syntax")
+
+// ParseDecimal parses a decimal number made of an optional sign, digits and
+// an optional fractional part, e.g. `-5` or `19.90`.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return Decimal{}, errDecimalSyntax
+	}
+	var scale int
+	if index := strings.IndexByte(digits, '.'); index >= 0 {
+		scale = len(digits) - index - 1
+		digits = digits[:index] + digits[index+1:]
+	}
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Decimal{}, errDecimalSyntax
+	}
+	unscaled, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Decimal{}, errors.New("ogp: decimal out of range")
+	}
+	if strings.HasPrefix(s, "-") {
+		unscaled = -unscaled
+	}
+	return Decimal{unscaled: unscaled, scale: scale}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s is not a valid
+// decimal number. It eases the use of decimal constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	switch {
+	case d.unscaled < 0:
+		return -1
+	case d.unscaled > 0:
+		return 1
+	}
+	return 0
+}
+
+func (d Decimal) String() string {
+	s := strconv.FormatInt(d.unscaled, 10)
+	if d.scale == 0 {
+		return s
+	}
+	sign := ""
+	if d.unscaled < 0 {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= d.scale {
+		s = strings.Repeat("0", d.scale-len(s)+1) + s
+	}
+	return sign + s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+}
+
+// MarshalText encodes d as its string representation, so that it doesn't
+// lose precision in JSON.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a decimal number encoded by MarshalText.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	decimal, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = decimal
+	return nil
+}
@@ -0,0 +1,121 @@
+package ogp
+
+import "net/url"
+
+// MergeMode tells how a list-valued property of the defaults is merged with
+// the values set on an object.
+type MergeMode int
+
+const (
+	// MergeReplace uses the default values only when the object has none of
+	// its own.
+	MergeReplace MergeMode = iota
+	// MergeAppend renders the default values after the ones of the object.
+	MergeAppend
+)
+
+// Defaults holds the site-wide properties merged into every object at render
+// time. The values set on an object always win over the defaults.
+type Defaults struct {
+	// Base is the URL the relative URLs of objects are resolved against,
+	// e.g. the origin of the site. The URLs are then normalized: their
+	// scheme and host are lowercased, and default ports are dropped.
+	Base *url.URL
+	// SiteName is the `og:site_name` of objects without one.
+	SiteName string
+	// Locales are the `og:locale` and `og:locale:alternate` properties,
+	// merged according to LocaleMode.
+	Locales    []string
+	LocaleMode MergeMode
+	// Images are the fallback `og:image` properties, merged according to
+	// ImageMode.
+	Images    []ImageData
+	ImageMode MergeMode
+	// FacebookAppID is the `fb:app_id` property.
+	FacebookAppID string
+	// TwitterSite and TwitterCreator are the `twitter:site` and
+	// `twitter:creator` properties of Twitter Cards.
+	TwitterSite    string
+	TwitterCreator string
+}
+
+func (d *WebsiteData) base() *url.URL {
+	if d.defaults == nil {
+		return nil
+	}
+	return d.defaults.Base
+}
+
+func (d *WebsiteData) siteName() string {
+	if d.SiteName == "" && d.defaults != nil {
+		return d.defaults.SiteName
+	}
+	return d.SiteName
+}
+
+func (d *WebsiteData) locales() []string {
+	if d.defaults == nil || len(d.defaults.Locales) == 0 {
+		return d.Locales
+	}
+	if len(d.Locales) == 0 {
+		return d.defaults.Locales
+	}
+	if d.defaults.LocaleMode != MergeAppend {
+		return d.Locales
+	}
+	locales := append([]string(nil), d.Locales...)
+	for _, locale := range d.defaults.Locales {
+		if !containsString(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+func (d *WebsiteData) images() []ImageData {
+	if d.defaults == nil || len(d.defaults.Images) == 0 {
+		return d.Images
+	}
+	if len(d.Images) == 0 {
+		return d.defaults.Images
+	}
+	if d.defaults.ImageMode != MergeAppend {
+		return d.Images
+	}
+	images := append([]ImageData(nil), d.Images...)
+	for _, image := range d.defaults.Images {
+		if !containsImage(images, image.URL) {
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+func (d *WebsiteData) facebook() FacebookData {
+	var facebook FacebookData
+	if d.Facebook != nil {
+		facebook = *d.Facebook
+	}
+	if facebook.AppID == "" && d.defaults != nil {
+		facebook.AppID = d.defaults.FacebookAppID
+	}
+	return facebook
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsImage(images []ImageData, url string) bool {
+	for _, image := range images {
+		if image.URL == url {
+			return true
+		}
+	}
+	return false
+}
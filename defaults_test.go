@@ -0,0 +1,156 @@
+package ogp_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestDefaults(t *testing.T) {
+	defaults := func(localeMode, imageMode ogp.MergeMode) *ogp.Defaults {
+		return &ogp.Defaults{
+			SiteName:      "Acme",
+			Locales:       []string{"en_US", "fr_FR"},
+			LocaleMode:    localeMode,
+			Images:        []ogp.ImageData{{URL: "http://example.com/logo.png", Width: 1200, Height: 630}},
+			ImageMode:     imageMode,
+			FacebookAppID: "1234",
+		}
+	}
+	tests := []struct {
+		object   ogp.Object
+		expected string
+	}{
+		{
+			object: ogp.Website().Title("Home").URL("http://example.com").
+				Defaults(defaults(ogp.MergeReplace, ogp.MergeReplace)),
+			expected: `<meta property="og:type" content="website">
+<meta property="og:title" content="Home">
+<meta property="og:url" content="http://example.com">
+<meta property="og:locale" content="en_US">
+<meta property="og:locale:alternate" content="fr_FR">
+<meta property="og:site_name" content="Acme">
+<meta property="og:image" content="http://example.com/logo.png">
+<meta property="og:image:width" content="1200">
+<meta property="og:image:height" content="630">
+<meta property="fb:app_id" content="1234">`,
+		},
+		{
+			object: ogp.Article().Title("News").URL("http://example.com/news").SiteName("Acme News").
+				Locale("de_DE").Image(ogp.Image().URL("http://example.com/news.png")).
+				Defaults(defaults(ogp.MergeReplace, ogp.MergeReplace)),
+			expected: `<meta property="og:type" content="article">
+<meta property="og:title" content="News">
+<meta property="og:url" content="http://example.com/news">
+<meta property="og:locale" content="de_DE">
+<meta property="og:site_name" content="Acme News">
+<meta property="og:image" content="http://example.com/news.png">
+<meta property="fb:app_id" content="1234">`,
+		},
+		{
+			object: ogp.Article().Title("News").URL("http://example.com/news").
+				Locale("fr_FR").Image(ogp.Image().URL("http://example.com/news.png")).
+				Defaults(defaults(ogp.MergeAppend, ogp.MergeAppend)),
+			expected: `<meta property="og:type" content="article">
+<meta property="og:title" content="News">
+<meta property="og:url" content="http://example.com/news">
+<meta property="og:locale" content="fr_FR">
+<meta property="og:locale:alternate" content="en_US">
+<meta property="og:site_name" content="Acme">
+<meta property="og:image" content="http://example.com/news.png">
+<meta property="og:image" content="http://example.com/logo.png">
+<meta property="og:image:width" content="1200">
+<meta property="og:image:height" content="630">
+<meta property="fb:app_id" content="1234">`,
+		},
+		{
+			object: ogp.Article().Title("News").URL("http://example.com/news").
+				Author(ogp.Profile().URL("http://example.com/jsmith").Defaults(defaults(ogp.MergeReplace, ogp.MergeReplace))),
+			expected: `<meta property="og:type" content="article">
+<meta property="og:title" content="News">
+<meta property="og:url" content="http://example.com/news">
+<meta property="article:author" content="http://example.com/jsmith">`,
+		},
+	}
+	for _, test := range tests {
+		if result := test.object.String(); result != test.expected {
+			t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, test.expected)
+		}
+	}
+}
+
+func TestDefaultsValidate(t *testing.T) {
+	website := ogp.Website().Title("Home").URL("http://example.com")
+	if err := website.Validate(); err == nil {
+		t.Error("expected an error without image")
+	}
+	website.Defaults(&ogp.Defaults{Images: []ogp.ImageData{{URL: "http://example.com/logo.png"}}})
+	if err := website.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDefaultsTwitterCard(t *testing.T) {
+	defaults := &ogp.Defaults{TwitterSite: "@acme", TwitterCreator: "@acme"}
+	card := ogp.TwitterCard(ogp.Website()).Creator("@jsmith").Defaults(defaults)
+	expected := `<meta name="twitter:card" content="summary">
+<meta name="twitter:site" content="@acme">
+<meta name="twitter:creator" content="@jsmith">`
+	if result := card.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestDefaultsBase(t *testing.T) {
+	base, _ := url.Parse("HTTP://Example.COM:80/blog/")
+	defaults := &ogp.Defaults{Base: base}
+	article := ogp.Article().Title("News").URL("news/1").
+		Image(ogp.Image().URL("/img/a.png").SecureURL("HTTPS://CDN.Example.com:443/a.png")).
+		Video(ogp.Video().URL("video.mp4").SecureURL("https://example.com:8443/video.mp4")).
+		Audio(ogp.Audio().URL("//Media.Example.com/audio.mp3")).
+		SeeAlso("../about").Author(ogp.Profile().URL("/people/alice").Image(ogp.Image().URL("alice.png"))).
+		AppLink(ogp.AppLink().IOS("1234", "example://news/1", "Example")).
+		Property("acme", "url", "relative").Namespace("acme", "https://example.com/ns/acme#").
+		Defaults(defaults)
+	expected := `<meta property="og:type" content="article">
+<meta property="og:title" content="News">
+<meta property="og:url" content="http://example.com/blog/news/1">
+<meta property="og:image" content="http://example.com/img/a.png">
+<meta property="og:image:secure_url" content="https://cdn.example.com/a.png">
+<meta property="og:video" content="http://example.com/blog/video.mp4">
+<meta property="og:video:secure_url" content="https://example.com:8443/video.mp4">
+<meta property="og:audio" content="http://media.example.com/audio.mp3">
+<meta property="og:see_also" content="http://example.com/about">
+<meta property="al:ios:url" content="example://news/1">
+<meta property="al:ios:app_store_id" content="1234">
+<meta property="al:ios:app_name" content="Example">
+<meta property="article:author" content="http://example.com/people/alice">
+<meta property="article:author:image" content="http://example.com/blog/alice.png">
+<meta property="acme:url" content="relative">`
+	if result := article.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if err := article.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if result := string(article.JSONLD()); !strings.Contains(result, `"url":"http://example.com/blog/news/1"`) ||
+		!strings.Contains(result, `"url":"http://example.com/people/alice"`) {
+		t.Errorf("unexpected JSON-LD: %s", result)
+	}
+	episode := ogp.Episode().Title("Pilot").URL("/show/1").Series(ogp.TVShow().URL("/show")).Defaults(defaults)
+	song := ogp.Song().Title("Song").URL("/song").Album("/album", 1, 2).Defaults(defaults)
+	for object, expected := range map[ogp.Object]string{
+		episode: `<meta property="video:series" content="http://example.com/show">`,
+		song:    `<meta property="music:album" content="http://example.com/album">`,
+	} {
+		if result := object.String(); !strings.Contains(result, expected) {
+			t.Errorf("unexpected result:\n%s", result)
+		}
+	}
+	card := ogp.TwitterCard(article).Image("card.png").Defaults(defaults)
+	if result := card.String(); !strings.Contains(result, `<meta name="twitter:image" content="http://example.com/blog/card.png">`) {
+		t.Errorf("unexpected result:\n%s", result)
+	}
+}
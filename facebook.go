@@ -0,0 +1,68 @@
+package ogp
+
+// FacebookData holds the properties of the `fb` namespace, used by Facebook
+// to attribute shared pages to an app, its administrators and its pages.
+type FacebookData struct {
+	AppID  string   `json:"app_id,omitempty"`
+	Admins []string `json:"admins,omitempty"`
+	Pages  []string `json:"pages,omitempty"`
+}
+
+// FacebookBuilder builds the `fb` properties of an object.
+type FacebookBuilder struct {
+	data FacebookData
+}
+
+// AppID sets the `fb:app_id` property.
+func (b *FacebookBuilder) AppID(appID string) *FacebookBuilder {
+	b.data.AppID = appID
+	return b
+}
+
+// Admin adds a new `fb:admins` property, the ID of a Facebook user
+// administrating the page.
+func (b *FacebookBuilder) Admin(admin string) *FacebookBuilder {
+	b.data.Admins = append(b.data.Admins, admin)
+	return b
+}
+
+// Page adds a new `fb:pages` property, the ID of a Facebook page the website
+// belongs to.
+func (b *FacebookBuilder) Page(page string) *FacebookBuilder {
+	b.data.Pages = append(b.data.Pages, page)
+	return b
+}
+
+// Data returns the `fb` properties. Changes made to the returned value are
+// reflected in the builder.
+func (b *FacebookBuilder) Data() *FacebookData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the `fb` properties.
+func (d FacebookData) Builder() *FacebookBuilder {
+	return &FacebookBuilder{data: d}
+}
+
+func (d *FacebookData) meta(mb *metaBuilder) {
+	if d.AppID != "" {
+		mb.Add("fb", "app_id", d.AppID)
+	}
+	for _, admin := range d.Admins {
+		mb.Add("fb", "admins", admin)
+	}
+	for _, page := range d.Pages {
+		mb.Add("fb", "pages", page)
+	}
+}
+
+func (d *FacebookData) decode(g *group) {
+	switch g.Name {
+	case "fb:app_id":
+		d.AppID = g.Content
+	case "fb:admins":
+		d.Admins = append(d.Admins, g.Content)
+	case "fb:pages":
+		d.Pages = append(d.Pages, g.Content)
+	}
+}
@@ -0,0 +1,75 @@
+package ogp_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestFacebookArticle(t *testing.T) {
+	article := ogp.Article().
+		URL("http://www.nytimes.com/2015/02/19/arts/international/when-great-minds-dont-think-alike.html").
+		Title("When Great Minds Don’t Think Alike").
+		Description("How much does culture influence creative thinking?").
+		Image(ogp.Image().URL("http://static01.nyt.com/images/2015/02/19/arts/international/19iht-btnumbers19A/19iht-btnumbers19A-facebookJumbo-v2.jpg")).
+		Author(ogp.Profile().URL("https://www.facebook.com/nytimes")).
+		Publisher("https://www.facebook.com/nytimes").
+		Facebook(ogp.Facebook().AppID("966242223397117"))
+	expected := []ogp.Property{
+		{Name: "og:url", Content: "http://www.nytimes.com/2015/02/19/arts/international/when-great-minds-dont-think-alike.html"},
+		{Name: "og:type", Content: "article"},
+		{Name: "og:title", Content: "When Great Minds Don’t Think Alike"},
+		{Name: "og:description", Content: "How much does culture influence creative thinking?"},
+		{Name: "og:image", Content: "http://static01.nyt.com/images/2015/02/19/arts/international/19iht-btnumbers19A/19iht-btnumbers19A-facebookJumbo-v2.jpg"},
+		{Name: "fb:app_id", Content: "966242223397117"},
+		{Name: "article:author", Content: "https://www.facebook.com/nytimes"},
+		{Name: "article:publisher", Content: "https://www.facebook.com/nytimes"},
+	}
+	result := article.Properties()
+	sortProperties(result)
+	sortProperties(expected)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected properties:\n%v\nexpected:\n%v", result, expected)
+	}
+	if err := article.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if prefix := ogp.Prefix(article); prefix != "og: https://ogp.me/ns# article: https://ogp.me/ns/article# fb: https://ogp.me/ns/fb#" {
+		t.Errorf("unexpected prefix: %s", prefix)
+	}
+}
+
+func TestFacebookDefaults(t *testing.T) {
+	defaults := &ogp.Defaults{FacebookAppID: "1234"}
+	tests := []struct {
+		object   ogp.Object
+		expected string
+	}{
+		{
+			object: ogp.Website().Defaults(defaults).Facebook(ogp.Facebook().Admin("42").Admin("43").Page("7")),
+			expected: `<meta property="og:type" content="website">
+<meta property="fb:app_id" content="1234">
+<meta property="fb:admins" content="42">
+<meta property="fb:admins" content="43">
+<meta property="fb:pages" content="7">`,
+		},
+		{
+			object: ogp.Website().Defaults(defaults).Facebook(ogp.Facebook().AppID("5678")),
+			expected: `<meta property="og:type" content="website">
+<meta property="fb:app_id" content="5678">`,
+		},
+	}
+	for _, test := range tests {
+		if result := test.object.String(); result != test.expected {
+			t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, test.expected)
+		}
+	}
+}
+
+func sortProperties(props []ogp.Property) {
+	sort.Slice(props, func(i, j int) bool {
+		return props[i].Name < props[j].Name
+	})
+}
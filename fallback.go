@@ -0,0 +1,340 @@
+package ogp
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Source is the metadata of a document that a property missing from its
+// Open Graph properties is taken from.
+type Source string
+
+// Sources of the properties filled in by a Parser with Fallback.
+const (
+	// SourceTitle is the `<title>` element.
+	SourceTitle Source = "title"
+	// SourceDescription is the `<meta name="description">` element.
+	SourceDescription Source = "description"
+	// SourceTwitter is the `twitter:*` properties of a Twitter Card.
+	SourceTwitter Source = "twitter"
+	// SourceCanonical is the `<link rel="canonical">` element.
+	SourceCanonical Source = "canonical"
+	// SourceLang is the `lang` attribute of the `<html>` element.
+	SourceLang Source = "lang"
+	// SourceJSONLD is the schema.org JSON-LD of the document.
+	SourceJSONLD Source = "json-ld"
+	// SourceImage is the largest `<img>` element of the document, among the
+	// ones whose `width` and `height` attributes are set.
+	SourceImage Source = "img"
+)
+
+// fallback holds the metadata of a document other than its Open Graph
+// properties, collected by extract.
+type fallback struct {
+	lang        string
+	title       string
+	description string
+	canonical   string
+	twitter     map[string]string
+	linkedData  []string
+	image       *ImageData
+	// capture is the element whose text is expected next, if any.
+	capture string
+}
+
+// scan collects the metadata held by t.
+func (f *fallback) scan(t *token) {
+	switch t.typ {
+	case textToken:
+		switch f.capture {
+		case "title":
+			if f.title == "" {
+				f.title = strings.Join(strings.Fields(t.text), " ")
+			}
+		case "script":
+			f.linkedData = append(f.linkedData, t.text)
+		}
+		f.capture = ""
+		return
+	case endTagToken:
+		f.capture = ""
+		return
+	}
+	switch t.name {
+	case "html":
+		if lang, ok := t.attr("lang"); ok && f.lang == "" {
+			f.lang = strings.TrimSpace(lang)
+		}
+	case "title":
+		f.capture = "title"
+	case "script":
+		if typ, _ := t.attr("type"); strings.EqualFold(strings.TrimSpace(typ), "application/ld+json") {
+			f.capture = "script"
+		}
+	case "meta":
+		name, ok := t.attr("name")
+		if !ok {
+			name, _ = t.attr("property")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		content, _ := t.attr("content")
+		content = strings.TrimSpace(content)
+		switch {
+		case content == "":
+		case name == "description":
+			if f.description == "" {
+				f.description = content
+			}
+		case strings.HasPrefix(name, "twitter:"):
+			if f.twitter == nil {
+				f.twitter = make(map[string]string)
+			}
+			if _, ok := f.twitter[name]; !ok {
+				f.twitter[name] = content
+			}
+		}
+	case "link":
+		rel, _ := t.attr("rel")
+		href, _ := t.attr("href")
+		for _, r := range strings.Fields(rel) {
+			if strings.EqualFold(r, "canonical") && f.canonical == "" {
+				f.canonical = strings.TrimSpace(href)
+			}
+		}
+	case "img":
+		src, _ := t.attr("src")
+		width, _ := t.attr("width")
+		height, _ := t.attr("height")
+		alt, _ := t.attr("alt")
+		image := ImageData{URL: strings.TrimSpace(src), Alt: alt, Width: parseInt(width), Height: parseInt(height)}
+		if image.URL == "" || image.Width <= 0 || image.Height <= 0 {
+			break
+		}
+		if f.image == nil || image.Width*image.Height > f.image.Width*f.image.Height {
+			f.image = &image
+		}
+	}
+}
+
+// fill returns props completed with the properties they miss, taken from
+// the other metadata of the document, along with the sources of the
+// properties added.
+func (f *fallback) fill(props []Property) ([]Property, map[string]Source) {
+	has := make(map[string]bool)
+	for _, p := range props {
+		switch p.Name {
+		case "og:image:url", "og:image:secure_url":
+			// Both start an image as well as `og:image` does.
+			has["og:image"] = true
+		default:
+			has[p.Name] = true
+		}
+	}
+	result := append([]Property(nil), props...)
+	sources := make(map[string]Source)
+	add := func(name string, source Source, candidates ...string) {
+		if has[name] || len(candidates) == 0 || candidates[0] == "" {
+			return
+		}
+		result = append(result, Property{Name: name, Content: candidates[0]})
+		for _, structured := range candidates[1:] {
+			if index := strings.IndexByte(structured, '='); index > 0 && structured[index+1:] != "" {
+				result = append(result, Property{Name: name + ":" + structured[:index], Content: structured[index+1:]})
+			}
+		}
+		has[name] = true
+		sources[name] = source
+	}
+	ld := f.node()
+	typ := ""
+	for _, p := range props {
+		if p.Name == "og:type" {
+			typ = p.Content
+			break
+		}
+	}
+	if typ == "" && isArticle(ld) {
+		typ = "article"
+		add("og:type", SourceJSONLD, typ)
+	}
+	add("og:title", SourceTwitter, f.twitter["twitter:title"])
+	add("og:title", SourceJSONLD, ldString(ld["headline"]))
+	if hasType(ld, "WebSite") {
+		add("og:site_name", SourceJSONLD, ldString(ld["name"]))
+	} else {
+		add("og:title", SourceJSONLD, ldString(ld["name"]))
+	}
+	add("og:title", SourceTitle, f.title)
+	add("og:description", SourceTwitter, f.twitter["twitter:description"])
+	add("og:description", SourceJSONLD, ldString(ld["description"]))
+	add("og:description", SourceDescription, f.description)
+	add("og:url", SourceCanonical, f.canonical)
+	add("og:url", SourceJSONLD, ldString(ld["url"]))
+	add("og:locale", SourceLang, locale(f.lang))
+	add("og:locale", SourceJSONLD, locale(ldString(ld["inLanguage"])))
+	add("og:site_name", SourceJSONLD, ldString(ldNode(ld["publisher"])["name"]))
+	twitterImage := f.twitter["twitter:image"]
+	if twitterImage == "" {
+		twitterImage = f.twitter["twitter:image:src"]
+	}
+	add("og:image", SourceTwitter, twitterImage, "alt="+f.twitter["twitter:image:alt"])
+	if image := ldString(ld["image"]); image != "" {
+		add("og:image", SourceJSONLD, image)
+	} else if image := ldNode(ld["image"]); image != nil {
+		add("og:image", SourceJSONLD, ldString(image["url"]), "width="+ldString(image["width"]), "height="+ldString(image["height"]))
+	}
+	if f.image != nil {
+		add("og:image", SourceImage, f.image.URL,
+			"width="+strconv.Itoa(f.image.Width), "height="+strconv.Itoa(f.image.Height), "alt="+f.image.Alt)
+	}
+	if typ != "article" {
+		return result, sources
+	}
+	for _, prop := range [][2]string{{"article:published_time", "datePublished"}, {"article:modified_time", "dateModified"}} {
+		if _, ok := parseTime(ldString(ld[prop[1]])); ok {
+			add(prop[0], SourceJSONLD, ldString(ld[prop[1]]))
+		}
+	}
+	if !has["article:author"] {
+		// Authors are referenced by URL, so the ones without a URL are
+		// left out.
+		for _, author := range ldNodes(ld["author"]) {
+			if url := ldString(author["url"]); url != "" {
+				result = append(result, Property{Name: "article:author", Content: url})
+				if name := ldString(author["name"]); name != "" {
+					result = append(result, Property{Name: "article:author:title", Content: name})
+				}
+				sources["article:author"] = SourceJSONLD
+			}
+		}
+	}
+	return result, sources
+}
+
+// node returns the main schema.org node of the JSON-LD of the document,
+// preferably an article, then a web page, then a website.
+func (f *fallback) node() map[string]interface{} {
+	var nodes []map[string]interface{}
+	for _, s := range f.linkedData {
+		var v interface{}
+		if json.Unmarshal([]byte(s), &v) == nil {
+			nodes = appendNodes(nodes, v)
+		}
+	}
+	for _, match := range []func(map[string]interface{}) bool{
+		isArticle,
+		func(n map[string]interface{}) bool { return hasType(n, "Page") },
+		func(n map[string]interface{}) bool { return hasType(n, "WebSite") },
+	} {
+		for _, n := range nodes {
+			if match(n) {
+				return n
+			}
+		}
+	}
+	return nil
+}
+
+// appendNodes appends to nodes the typed schema.org nodes held by v, which
+// may be an array or hold a `@graph`.
+func appendNodes(nodes []map[string]interface{}, v interface{}) []map[string]interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			nodes = appendNodes(nodes, item)
+		}
+	case map[string]interface{}:
+		if _, ok := v["@type"]; ok {
+			nodes = append(nodes, v)
+		}
+		nodes = appendNodes(nodes, v["@graph"])
+	}
+	return nodes
+}
+
+// isArticle reports whether n is a schema.org article or post, e.g. a
+// NewsArticle or a BlogPosting.
+func isArticle(n map[string]interface{}) bool {
+	return hasType(n, "Article") || hasType(n, "Posting")
+}
+
+// hasType reports whether one of the schema.org types of n ends with typ,
+// e.g. an AboutPage for Page.
+func hasType(n map[string]interface{}, typ string) bool {
+	for _, t := range ldNodeTypes(n) {
+		if strings.HasSuffix(t, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+func ldNodeTypes(n map[string]interface{}) []string {
+	var result []string
+	switch typ := n["@type"].(type) {
+	case string:
+		result = append(result, typ)
+	case []interface{}:
+		for _, t := range typ {
+			result = append(result, ldString(t))
+		}
+	}
+	return result
+}
+
+// ldString returns v as a string if it is a string or a number, or the
+// first of them if it is an array.
+func ldString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		if len(v) > 0 {
+			return ldString(v[0])
+		}
+	}
+	return ""
+}
+
+// ldNode returns v as a node, or the first one if it is an array.
+func ldNode(v interface{}) map[string]interface{} {
+	if nodes := ldNodes(v); len(nodes) > 0 {
+		return nodes[0]
+	}
+	return nil
+}
+
+// ldNodes returns the nodes held by v, a node or an array. Strings are taken
+// as the names of the nodes.
+func ldNodes(v interface{}) []map[string]interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case string:
+		return []map[string]interface{}{{"name": v}}
+	case []interface{}:
+		var result []map[string]interface{}
+		for _, item := range v {
+			result = append(result, ldNodes(item)...)
+		}
+		return result
+	}
+	return nil
+}
+
+// locale converts a language tag, e.g. `en-us`, to an Open Graph locale,
+// e.g. `en_US`.
+func locale(tag string) string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return ""
+	}
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) > 1 && len(parts[1]) == 2 {
+		return parts[0] + "_" + strings.ToUpper(parts[1])
+	}
+	return parts[0]
+}
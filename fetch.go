@@ -0,0 +1,226 @@
+package ogp
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	neturl "net/url"
+	"sync"
+)
+
+// Default limits of a Fetcher.
+const (
+	DefaultMaxRedirects = 10
+	DefaultMaxBytes     = 1 << 20
+)
+
+// DefaultUserAgent is the User-Agent header sent by a Fetcher.
+const DefaultUserAgent = "ogp.v1 (+https://gopkg.in/ogp.v1)"
+
+// ErrNotHTML is returned by Fetch when the response isn't an HTML document.
+var ErrNotHTML = errors.New("ogp: response is not an HTML document")
+
+// ErrTooManyRedirects is returned by Fetch when the URL redirects more times
+// than allowed.
+var ErrTooManyRedirects = errors.New("ogp: too many redirects")
+
+// Fetcher fetches the Open Graph objects of remote documents. The zero value
+// is ready to use. A Fetcher must not be modified once used, and is safe for
+// concurrent use.
+type Fetcher struct {
+	// Client sends the requests, http.DefaultClient if nil. Its transport
+	// is copied or wrapped to enforce Policy.
+	Client *http.Client
+	// Policy restricts the URLs requested and the addresses connected to,
+	// the zero FetchPolicy if nil.
+	Policy *FetchPolicy
+	// MaxRedirects is the number of redirects followed, DefaultMaxRedirects
+	// if zero. A negative value follows none.
+	MaxRedirects int
+	// MaxBytes is the number of bytes of the document read, once decoded,
+	// DefaultMaxBytes if zero. The properties found after are ignored.
+	MaxBytes int64
+	// UserAgent is the User-Agent header of the requests, DefaultUserAgent
+	// if empty.
+	UserAgent string
+	// CharsetReader decodes the documents whose charset is neither UTF-8,
+	// UTF-16, ISO-8859-1 nor windows-1252, which are built in. Such
+	// documents are rejected if nil.
+	CharsetReader CharsetReader
+	// Fallback fills in the properties missing from the document, as the
+	// Fallback of a Parser does. The whole document is then read, up to
+	// MaxBytes, rather than its head only.
+	Fallback bool
+
+	once       sync.Once
+	httpClient *http.Client
+}
+
+var defaultFetcher Fetcher
+
+// Fetch fetches the Open Graph object of the document at url with a zero
+// Fetcher.
+func Fetch(ctx context.Context, url string) (Object, string, error) {
+	return defaultFetcher.Fetch(ctx, url)
+}
+
+// Fetch fetches the document at url and parses its Open Graph object, which
+// is returned along with the URL of the document once redirects are
+// followed. The errors of the client, e.g. ErrTooManyRedirects or a
+// *BlockedError, are wrapped in a *url.Error.
+//
+// Only the head of the document is read, up to MaxBytes. Gzip-encoded
+// documents are decoded, and the charset of the document is detected from
+// its byte order mark, its Content-Type header or a `<meta>` element. The
+// relative URLs of the document are resolved against its URL, as the Base
+// of a Parser.
+func (f *Fetcher) Fetch(ctx context.Context, url string) (Object, string, error) {
+	doc, err := f.fetch(ctx, url, nil)
+	if doc == nil {
+		return nil, "", err
+	}
+	return doc.object, doc.url, err
+}
+
+// document is a fetched document.
+type document struct {
+	object Object
+	data   *WebsiteData
+	// url is the URL of the document once redirects are followed.
+	url    string
+	header http.Header
+	// notModified tells whether the server answered a conditional request
+	// with 304 Not Modified, in which case there is no object.
+	notModified bool
+}
+
+// fetch fetches the document at url with the additional header, e.g. the
+// validators of a conditional request. The document is returned along with
+// the error if a response was received.
+func (f *Fetcher) fetch(ctx context.Context, url string, header http.Header) (*document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.policy().checkURL(req.URL); err != nil {
+		return nil, &neturl.Error{Op: "Get", URL: url, Err: err}
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("User-Agent", f.userAgent())
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := f.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	doc := &document{url: resp.Request.URL.String(), header: resp.Header}
+	if resp.StatusCode == http.StatusNotModified && len(header) > 0 {
+		doc.notModified = true
+		return doc, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return doc, fmt.Errorf("ogp: unexpected status %s", resp.Status)
+	}
+	doc.object, doc.data, err = f.parse(resp)
+	return doc, err
+}
+
+// parse parses the body of resp.
+func (f *Fetcher) parse(resp *http.Response) (Object, *WebsiteData, error) {
+	var body io.Reader = resp.Body
+	switch resp.Header.Get("Content-Encoding") {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer zr.Close()
+		body = zr
+	default:
+		return nil, nil, fmt.Errorf("ogp: unsupported content encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+	br := bufio.NewReaderSize(io.LimitReader(body, f.maxBytes()), prescanSize)
+	contentType := resp.Header.Get("Content-Type")
+	sniffed := contentType
+	if sniffed == "" {
+		peek, _ := br.Peek(prescanSize)
+		sniffed = http.DetectContentType(peek)
+	}
+	if typ, _, _ := mime.ParseMediaType(sniffed); typ != "text/html" && typ != "application/xhtml+xml" {
+		return nil, nil, ErrNotHTML
+	}
+	r, err := decodeCharset(br, contentType, f.CharsetReader)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := Parser{Fallback: f.Fallback, Base: resp.Request.URL}
+	return p.parse(r, !f.Fallback)
+}
+
+// client returns a copy of the client of f which enforces MaxRedirects and
+// Policy.
+func (f *Fetcher) client() *http.Client {
+	f.once.Do(func() {
+		client := http.DefaultClient
+		if f.Client != nil {
+			client = f.Client
+		}
+		policy := f.policy()
+		c := *client
+		c.Transport = policy.transport(client.Transport)
+		c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) > f.maxRedirects() {
+				return ErrTooManyRedirects
+			}
+			if err := policy.checkURL(req.URL); err != nil {
+				return err
+			}
+			if client.CheckRedirect != nil {
+				return client.CheckRedirect(req, via)
+			}
+			return nil
+		}
+		f.httpClient = &c
+	})
+	return f.httpClient
+}
+
+func (f *Fetcher) policy() *FetchPolicy {
+	if f.Policy == nil {
+		return &FetchPolicy{}
+	}
+	return f.Policy
+}
+
+func (f *Fetcher) maxRedirects() int {
+	switch {
+	case f.MaxRedirects < 0:
+		return 0
+	case f.MaxRedirects == 0:
+		return DefaultMaxRedirects
+	}
+	return f.MaxRedirects
+}
+
+func (f *Fetcher) maxBytes() int64 {
+	if f.MaxBytes <= 0 {
+		return DefaultMaxBytes
+	}
+	return f.MaxBytes
+}
+
+func (f *Fetcher) userAgent() string {
+	if f.UserAgent == "" {
+		return DefaultUserAgent
+	}
+	return f.UserAgent
+}
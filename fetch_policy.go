@@ -0,0 +1,231 @@
+package ogp
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// BlockReason tells why a FetchPolicy blocks a request.
+type BlockReason string
+
+// Reasons of a BlockedError.
+const (
+	// BlockedScheme is the reason of a URL whose scheme isn't allowed.
+	BlockedScheme BlockReason = "scheme"
+	// BlockedPort is the reason of a URL whose port isn't allowed.
+	BlockedPort BlockReason = "port"
+	// BlockedAddress is the reason of a connection to a private, loopback,
+	// link-local, multicast or otherwise reserved IP address.
+	BlockedAddress BlockReason = "address"
+)
+
+// BlockedError is returned by Fetch, wrapped in a *url.Error, when the
+// FetchPolicy of the Fetcher blocks the URL, one of its redirects or the
+// address it resolves to.
+type BlockedError struct {
+	// Reason tells why the request was blocked.
+	Reason BlockReason
+	// Target is the blocked scheme, port or IP address.
+	Target string
+}
+
+func (e *BlockedError) Error() string {
+	return "ogp: blocked " + string(e.Reason) + " " + e.Target
+}
+
+// FetchPolicy restricts the URLs a Fetcher requests and the addresses it
+// connects to, so that the links pasted by users can't reach the internal
+// network. The zero value allows the http and https schemes on their default
+// ports, and blocks the private, loopback, link-local, multicast and
+// otherwise reserved IP addresses.
+//
+// The addresses are checked once resolved, right before connecting, which
+// defeats DNS rebinding, and every redirect is checked again. This holds
+// when the Transport of the client of the Fetcher is nil or an
+// *http.Transport, whose dialer is replaced. Other transports are wrapped so
+// that the addresses the host of each request resolves to are checked before
+// it is sent, which a DNS server answering differently the second time can
+// still get around. A proxy configured on the transport is checked instead
+// of the addresses it connects to, so the default transport doesn't use the
+// proxy of the environment.
+type FetchPolicy struct {
+	// Schemes are the URL schemes allowed, http and https if empty.
+	Schemes []string
+	// Ports are the ports allowed, 80 and 443 if empty. A URL without a
+	// port uses the default port of its scheme.
+	Ports []int
+	// AllowedNetworks are the networks allowed despite being blocked, e.g.
+	// a trusted internal service.
+	AllowedNetworks []*net.IPNet
+}
+
+// blockedNetworks are the IP networks that can't be reached by default.
+var blockedNetworks = parseNetworks(
+	"0.0.0.0/8",       // "This" network
+	"10.0.0.0/8",      // Private
+	"100.64.0.0/10",   // Shared address space
+	"127.0.0.0/8",     // Loopback
+	"169.254.0.0/16",  // Link-local
+	"172.16.0.0/12",   // Private
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // Documentation
+	"192.168.0.0/16",  // Private
+	"198.18.0.0/15",   // Benchmarking
+	"198.51.100.0/24", // Documentation
+	"203.0.113.0/24",  // Documentation
+	"224.0.0.0/4",     // Multicast
+	"240.0.0.0/4",     // Reserved and broadcast
+	"::/128",          // Unspecified
+	"::1/128",         // Loopback
+	"64:ff9b::/96",    // IPv4/IPv6 translation
+	"100::/64",        // Discard-only
+	"2001:db8::/32",   // Documentation
+	"fc00::/7",        // Unique local
+	"fe80::/10",       // Link-local
+	"ff00::/8",        // Multicast
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	result := make([]*net.IPNet, len(cidrs))
+	for index, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		result[index] = network
+	}
+	return result
+}
+
+// checkURL checks the scheme and the port of u.
+func (p *FetchPolicy) checkURL(u *url.URL) error {
+	scheme := strings.ToLower(u.Scheme)
+	schemes := p.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	allowed := false
+	for _, s := range schemes {
+		allowed = allowed || strings.EqualFold(s, scheme)
+	}
+	if !allowed {
+		return &BlockedError{Reason: BlockedScheme, Target: scheme}
+	}
+	port := u.Port()
+	if port == "" {
+		switch scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	ports := p.Ports
+	if len(ports) == 0 {
+		ports = []int{80, 443}
+	}
+	for _, allowed := range ports {
+		if port == strconv.Itoa(allowed) {
+			return nil
+		}
+	}
+	return &BlockedError{Reason: BlockedPort, Target: port}
+}
+
+// checkAddress checks the IP address of address, a host and port pair.
+func (p *FetchPolicy) checkAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return &BlockedError{Reason: BlockedAddress, Target: host}
+	}
+	return p.checkIP(ip)
+}
+
+// checkIP checks the IP address ip.
+func (p *FetchPolicy) checkIP(ip net.IP) error {
+	for _, network := range p.AllowedNetworks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return &BlockedError{Reason: BlockedAddress, Target: ip.String()}
+		}
+	}
+	return nil
+}
+
+// transport returns a copy of rt whose dialer checks the addresses it
+// connects to, or rt wrapped in a policyTransport if it isn't an
+// *http.Transport. A copy of http.DefaultTransport without proxy is used if
+// rt is nil.
+func (p *FetchPolicy) transport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = nil
+		rt = t
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return &policyTransport{policy: p, rt: rt}
+	}
+	t = t.Clone()
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			return p.checkAddress(address)
+		},
+	}
+	t.DialContext = dialer.DialContext
+	t.DialTLSContext = nil
+	t.DialTLS = nil
+	t.Dial = nil
+	return t
+}
+
+// policyTransport enforces a FetchPolicy on a RoundTripper whose dialer
+// can't be replaced, by checking the addresses the host of each request
+// resolves to before sending it.
+type policyTransport struct {
+	policy *FetchPolicy
+	rt     http.RoundTripper
+}
+
+func (t *policyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	err := t.policy.checkURL(req.URL)
+	if err == nil {
+		err = t.checkHost(req)
+	}
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.rt.RoundTrip(req)
+}
+
+// checkHost checks the addresses the host of req resolves to.
+func (t *policyTransport) checkHost(req *http.Request) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(req.Context(), req.URL.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if err := t.policy.checkIP(addr.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package ogp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/ogp.v1"
+)
+
+func title(t *testing.T, object ogp.Object) string {
+	t.Helper()
+	website, ok := object.(*ogp.WebsiteBuilder)
+	if !ok {
+		t.Fatalf("unexpected object: %#v", object)
+	}
+	return website.Data().Title
+}
+
+// local returns a policy allowing the loopback server.
+func local(server *httptest.Server) *ogp.FetchPolicy {
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	return &ogp.FetchPolicy{Ports: []int{port}, AllowedNetworks: []*net.IPNet{loopback}}
+}
+
+func fixtures() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><meta property="og:title" content="Home"></head></html>`)
+	})
+	mux.HandleFunc("/redirect/", func(w http.ResponseWriter, r *http.Request) {
+		var n int
+		fmt.Sscanf(r.URL.Path, "/redirect/%d", &n)
+		if n == 0 {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/redirect/%d", n-1), http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><meta property="og:title" content="Huge">`)
+		fmt.Fprint(w, strings.Repeat("<!-- padding -->", 1<<16))
+		fmt.Fprint(w, `<meta property="og:description" content="Too far"></head></html>`)
+	})
+	mux.HandleFunc("/endless", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><meta property="og:title" content="Endless"></head><body>`)
+		for {
+			if _, err := fmt.Fprint(w, strings.Repeat("<p>endless</p>", 1<<10)); err != nil {
+				return
+			}
+		}
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Plain</title></head><body><img src="/photo.jpg" width="800" height="600"></body></html>`)
+	})
+	mux.HandleFunc("/articles/relative", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><meta property="og:image" content="img/a.png"></head></html>`)
+	})
+	mux.HandleFunc("/to", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Query().Get("url"), http.StatusFound)
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, `<html><head><meta property="og:title" content="Image"></head></html>`)
+	})
+	mux.HandleFunc("/sniffed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header()["Content-Type"] = nil
+		fmt.Fprint(w, "\x89PNG\r\n\x1a\n")
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		fmt.Fprint(zw, `<html><head><meta property="og:title" content="Gzip"></head></html>`)
+		zw.Close()
+	})
+	mux.HandleFunc("/latin1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=ISO-8859-1")
+		fmt.Fprint(w, "<html><head><meta property=\"og:title\" content=\"Caf\xe9 \x80\"></head></html>")
+	})
+	mux.HandleFunc("/meta", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=windows-1252\">"+
+			"<meta property=\"og:title\" content=\"Caf\xe9\"></head></html>")
+	})
+	mux.HandleFunc("/utf16", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		var buf bytes.Buffer
+		buf.WriteString("\xff\xfe")
+		for _, r := range `<html><head><meta property="og:title" content="Café 😀"></head></html>` {
+			if r > 0xffff {
+				r -= 0x10000
+				buf.Write([]byte{byte(0xd800 + r>>10), byte((0xd800 + r>>10) >> 8), byte(0xdc00 + r&0x3ff), byte((0xdc00 + r&0x3ff) >> 8)})
+				continue
+			}
+			buf.Write([]byte{byte(r), byte(r >> 8)})
+		}
+		w.Write(buf.Bytes())
+	})
+	mux.HandleFunc("/bom", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		fmt.Fprint(w, "\xef\xbb\xbf<html><head><meta property=\"og:title\" content=\"Café\"></head></html>")
+	})
+	mux.HandleFunc("/koi8", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head><meta charset=\"koi8-r\"><meta property=\"og:title\" content=\"\xf0\xd2\xc9\xd7\xc5\xd4\"></head></html>")
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestFetch(t *testing.T) {
+	server := fixtures()
+	defer server.Close()
+	tests := []struct {
+		path  string
+		title string
+		url   string
+	}{
+		{"/", "Home", "/"},
+		{"/redirect/2", "Home", "/"},
+		{"/huge", "Huge", "/huge"},
+		{"/endless", "Endless", "/endless"},
+		{"/gzip", "Gzip", "/gzip"},
+		{"/latin1", "Café €", "/latin1"},
+		{"/meta", "Café", "/meta"},
+		{"/utf16", "Café 😀", "/utf16"},
+		{"/bom", "Café", "/bom"},
+	}
+	f := &ogp.Fetcher{Policy: local(server), MaxRedirects: 3, MaxBytes: 64 << 10}
+	for _, test := range tests {
+		object, url, err := f.Fetch(context.Background(), server.URL+test.path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.path, err)
+			continue
+		}
+		if result := title(t, object); result != test.title {
+			t.Errorf("%s: unexpected title: %q", test.path, result)
+		}
+		if url != server.URL+test.url {
+			t.Errorf("%s: unexpected URL: %s", test.path, url)
+		}
+		if result := object.(*ogp.WebsiteBuilder).Data().Description; result != "" {
+			t.Errorf("%s: unexpected description: %q", test.path, result)
+		}
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	server := fixtures()
+	defer server.Close()
+	tests := []struct {
+		path string
+		err  error
+	}{
+		{"/redirect/3", ogp.ErrTooManyRedirects},
+		{"/image.png", ogp.ErrNotHTML},
+		{"/sniffed", ogp.ErrNotHTML},
+	}
+	f := &ogp.Fetcher{Policy: local(server), MaxRedirects: 3}
+	for _, test := range tests {
+		_, _, err := f.Fetch(context.Background(), server.URL+test.path)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: unexpected error: %v", test.path, err)
+		}
+	}
+	if _, url, err := f.Fetch(context.Background(), server.URL+"/missing"); err == nil || url != server.URL+"/missing" {
+		t.Errorf("unexpected result: %s, %v", url, err)
+	}
+	if _, _, err := f.Fetch(context.Background(), server.URL+"/koi8"); err == nil {
+		t.Error("expected an error")
+	}
+	if _, _, err := (&ogp.Fetcher{Policy: local(server), MaxRedirects: -1}).Fetch(context.Background(), server.URL+"/redirect/0"); !errors.Is(err, ogp.ErrTooManyRedirects) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFetchCharsetReader(t *testing.T) {
+	server := fixtures()
+	defer server.Close()
+	koi8 := map[byte]rune{0xf0: 'П', 0xd2: 'р', 0xc9: 'и', 0xd7: 'в', 0xc5: 'е', 0xd4: 'т'}
+	f := &ogp.Fetcher{Policy: local(server), CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		if charset != "koi8-r" {
+			return nil, fmt.Errorf("unexpected charset %q", charset)
+		}
+		b, err := ioutil.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		var result strings.Builder
+		for _, c := range b {
+			if r, ok := koi8[c]; ok {
+				result.WriteRune(r)
+			} else {
+				result.WriteByte(c)
+			}
+		}
+		return strings.NewReader(result.String()), nil
+	}}
+	object, _, err := f.Fetch(context.Background(), server.URL+"/koi8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := title(t, object); result != "Привет" {
+		t.Errorf("unexpected title: %q", result)
+	}
+}
+
+func TestFetchFallback(t *testing.T) {
+	server := fixtures()
+	defer server.Close()
+	if _, _, err := (&ogp.Fetcher{Policy: local(server)}).Fetch(context.Background(), server.URL+"/plain"); err != ogp.ErrNoProperties {
+		t.Errorf("unexpected error: %v", err)
+	}
+	object, _, err := (&ogp.Fetcher{Policy: local(server), Fallback: true}).Fetch(context.Background(), server.URL+"/plain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := object.(*ogp.WebsiteBuilder).Data()
+	if data.Title != "Plain" || len(data.Images) != 1 || data.Sources["og:image"] != ogp.SourceImage {
+		t.Errorf("unexpected data: %+v", data)
+	}
+}
+
+func TestFetchBase(t *testing.T) {
+	server := fixtures()
+	defer server.Close()
+	f := &ogp.Fetcher{Policy: local(server)}
+	object, _, err := f.Fetch(context.Background(), server.URL+"/to?url=/articles/relative")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := object.(*ogp.WebsiteBuilder).Data().Images[0].URL; result != server.URL+"/articles/img/a.png" {
+		t.Errorf("unexpected image: %s", result)
+	}
+}
+
+func TestFetchPolicy(t *testing.T) {
+	server := fixtures()
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	permissive := &ogp.FetchPolicy{Ports: []int{port, 80}, AllowedNetworks: []*net.IPNet{loopback}}
+	redirect := func(to string) string {
+		return server.URL + "/to?url=" + url.QueryEscape(to)
+	}
+	tests := []struct {
+		policy *ogp.FetchPolicy
+		url    string
+		reason ogp.BlockReason
+		target string
+	}{
+		{nil, server.URL, ogp.BlockedPort, u.Port()},
+		{nil, "ftp://example.com/", ogp.BlockedScheme, "ftp"},
+		{nil, "http://localhost:6379/", ogp.BlockedPort, "6379"},
+		{nil, "http://169.254.169.254/", ogp.BlockedAddress, "169.254.169.254"},
+		{nil, "http://10.1.2.3/", ogp.BlockedAddress, "10.1.2.3"},
+		{nil, "http://0.0.0.0/", ogp.BlockedAddress, "0.0.0.0"},
+		{nil, "http://224.0.0.1/", ogp.BlockedAddress, "224.0.0.1"},
+		{nil, "http://[::ffff:127.0.0.1]/", ogp.BlockedAddress, "127.0.0.1"},
+		{nil, "http://[::1]/", ogp.BlockedAddress, "::1"},
+		{nil, "http://[fd00::1]/", ogp.BlockedAddress, "fd00::1"},
+		{&ogp.FetchPolicy{Ports: []int{port}}, server.URL, ogp.BlockedAddress, "127.0.0.1"},
+		{&ogp.FetchPolicy{Ports: []int{port}}, "http://localhost:" + u.Port(), ogp.BlockedAddress, ""},
+		{permissive, redirect("http://169.254.169.254/latest/meta-data/"), ogp.BlockedAddress, "169.254.169.254"},
+		{local(server), redirect("http://127.0.0.1:6379/"), ogp.BlockedPort, "6379"},
+		{local(server), redirect("file:///etc/passwd"), ogp.BlockedScheme, "file"},
+	}
+	// The policy holds for transports whose dialer can't be replaced too.
+	sent := 0
+	custom := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sent++
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	for _, client := range []*http.Client{nil, custom} {
+		for _, test := range tests {
+			f := &ogp.Fetcher{Client: client, Policy: test.policy}
+			_, _, err := f.Fetch(context.Background(), test.url)
+			var blocked *ogp.BlockedError
+			if !errors.As(err, &blocked) {
+				t.Errorf("%s: unexpected error: %v", test.url, err)
+				continue
+			}
+			if blocked.Reason != test.reason || test.target != "" && blocked.Target != test.target {
+				t.Errorf("%s: unexpected error: %v", test.url, err)
+			}
+		}
+	}
+	// Only the first request of the redirect tests gets through.
+	if sent != 3 {
+		t.Errorf("unexpected requests: %d", sent)
+	}
+	f := &ogp.Fetcher{Client: custom, Policy: local(server)}
+	if _, _, err := f.Fetch(context.Background(), server.URL); err != nil || sent != 4 {
+		t.Errorf("unexpected result: %v, %d", err, sent)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,124 @@
+package ogp
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// linkedData is a schema.org object, rendered as JSON-LD.
+type linkedData map[string]interface{}
+
+// renderLinkedData renders ld in a `<script type="application/ld+json">`
+// element, with its URLs resolved against base. The JSON encoder escapes
+// `<`, `>` and `&`, so the content can't close the element or open a
+// comment.
+func renderLinkedData(ld linkedData, base *url.URL) template.HTML {
+	resolveLinkedData(ld, base)
+	ld["@context"] = "https://schema.org"
+	var buf bytes.Buffer
+	buf.WriteString(`<script type="application/ld+json">`)
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(true)
+	if err := enc.Encode(ld); err != nil {
+		return ""
+	}
+	buf.Truncate(buf.Len() - 1)
+	buf.WriteString(`</script>`)
+	return template.HTML(buf.String())
+}
+
+// set sets the key property of ld, unless value is empty.
+func (ld linkedData) set(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return
+		}
+	case int:
+		if v <= 0 {
+			return
+		}
+	case *time.Time:
+		if v == nil {
+			return
+		}
+		value = v.Format(time.RFC3339)
+	case *DateTime:
+		if v == nil {
+			return
+		}
+		value = v.String()
+	case []string:
+		if len(v) == 0 {
+			return
+		}
+	case []linkedData:
+		if len(v) == 0 {
+			return
+		}
+	case linkedData:
+		if v == nil {
+			return
+		}
+	}
+	ld[key] = value
+}
+
+// baseLinkedData returns the schema.org object of the given type holding the
+// properties shared by every object type.
+func (d *WebsiteData) baseLinkedData(typ string) linkedData {
+	ld := linkedData{"@type": typ}
+	ld.set("name", d.Title)
+	ld.set("url", d.URL)
+	ld.set("description", d.Description)
+	ld.set("dateModified", d.UpdatedTime)
+	if locales := d.locales(); len(locales) > 0 {
+		ld.set("inLanguage", strings.Replace(locales[0], "_", "-", -1))
+	}
+	var images []string
+	for _, image := range d.images() {
+		if image.URL != "" {
+			images = append(images, image.URL)
+		}
+	}
+	if len(images) == 1 {
+		ld.set("image", images[0])
+	} else {
+		ld.set("image", images)
+	}
+	return ld
+}
+
+// isoDuration formats a duration, rounded to whole seconds, as an ISO 8601
+// duration.
+func isoDuration(d *time.Duration) string {
+	if d == nil || seconds(*d) <= 0 {
+		return ""
+	}
+	seconds := int(seconds(*d))
+	var sb strings.Builder
+	sb.WriteString("PT")
+	if h := seconds / 3600; h > 0 {
+		sb.WriteString(strconv.Itoa(h) + "H")
+	}
+	if m := seconds % 3600 / 60; m > 0 {
+		sb.WriteString(strconv.Itoa(m) + "M")
+	}
+	if s := seconds % 60; s > 0 {
+		sb.WriteString(strconv.Itoa(s) + "S")
+	}
+	return sb.String()
+}
+
+func peopleLinkedData(profiles []ProfileData) []linkedData {
+	var result []linkedData
+	for i := range profiles {
+		result = append(result, profiles[i].linkedData())
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package ogp_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestJSONLD(t *testing.T) {
+	date := time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		object   ogp.Object
+		expected string
+	}{
+		{
+			object: ogp.Article().Title("Article").URL("http://example.com/article").PublishedTime(date).
+				Author(ogp.Profile().URL("http://example.com/alice").FirstName("Alice").LastName("Smith")),
+			expected: `{"@context":"https://schema.org","@type":"Article","author":[{"@type":"Person","familyName":"Smith","givenName":"Alice","name":"Alice Smith","url":"http://example.com/alice"}],"datePublished":"2020-05-01T10:30:00Z","headline":"Article","name":"Article","url":"http://example.com/article"}`,
+		},
+		{
+			object:   ogp.Book().Title("Oliver Twist").ISBN("9780174325482"),
+			expected: `{"@context":"https://schema.org","@type":"Book","isbn":"9780174325482","name":"Oliver Twist"}`,
+		},
+		{
+			object:   ogp.Song().Title("Song").Duration(185*time.Second).Album("http://example.com/album", 1, 2),
+			expected: `{"@context":"https://schema.org","@type":"MusicRecording","duration":"PT3M5S","inAlbum":[{"@type":"MusicAlbum","url":"http://example.com/album"}],"name":"Song"}`,
+		},
+		{
+			object: ogp.Movie().Title("The Rock").Duration(136*time.Minute).
+				Actor(ogp.Profile().Title("Sean Connery"), "John Mason").Director(ogp.Profile().Title("Michael Bay")),
+			expected: `{"@context":"https://schema.org","@type":"Movie","actor":[{"@type":"PerformanceRole","actor":{"@type":"Person","name":"Sean Connery"},"characterName":"John Mason"}],"director":[{"@type":"Person","name":"Michael Bay"}],"duration":"PT2H16M","name":"The Rock"}`,
+		},
+		{
+			object:   ogp.Episode().Title("Pilot").Series(ogp.TVShow().Title("The Show").URL("http://example.com/show")),
+			expected: `{"@context":"https://schema.org","@type":"TVEpisode","name":"Pilot","partOfSeries":{"@type":"TVSeries","name":"The Show","url":"http://example.com/show"}}`,
+		},
+		{
+			object: ogp.Product().Title("Shoes").Price(ogp.MustParseDecimal("49.90"), "EUR").
+				Availability(ogp.AvailabilityInStock).Condition(ogp.ConditionNew).Brand("Acme").RetailerItemID("SH-42"),
+			expected: `{"@context":"https://schema.org","@type":"Product","brand":{"@type":"Brand","name":"Acme"},"itemCondition":"https://schema.org/NewCondition","name":"Shoes","offers":{"@type":"Offer","availability":"https://schema.org/InStock","price":"49.90","priceCurrency":"EUR"},"sku":"SH-42"}`,
+		},
+	}
+	for _, test := range tests {
+		result := string(test.object.JSONLD())
+		expected := `<script type="application/ld+json">` + test.expected + `</script>`
+		if result != expected {
+			t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+		}
+	}
+}
+
+func TestJSONLDEscaping(t *testing.T) {
+	const hostile = "</script><script>alert(1)</script><!--\u2028"
+	result := string(ogp.Article().Title(hostile).Description(hostile).JSONLD())
+	content := strings.TrimSuffix(strings.TrimPrefix(result, `<script type="application/ld+json">`), `</script>`)
+	if strings.ContainsAny(content, "<>&\u2028") {
+		t.Errorf("unsafe content: %s", content)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded["headline"] != hostile {
+		t.Errorf("unexpected headline: %v", decoded["headline"])
+	}
+}
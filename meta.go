@@ -1,35 +1,197 @@
 package ogp
 
 import (
-	"fmt"
-	"html/template"
+	"bytes"
+	"html"
+	"io"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
+// metaBuilder collects the properties of an object and renders them as meta
+// tags. Builders are pooled: get one with newMetaBuilder and release it once
+// rendered.
 type metaBuilder struct {
-	tags []string
+	props []metaProperty
+	// attr is the attribute holding the property names, `property` unless
+	// specified otherwise.
+	attr string
+	// base is the URL the URLs of the properties are resolved against, if
+	// any.
+	base *url.URL
 }
 
-func (b *metaBuilder) Add(ns, prop string, content interface{}) *metaBuilder {
-	var tag string
-	if prop != "" {
-		tag = fmt.Sprintf(`<meta property="%s:%s" content="%v">`, ns, prop, content)
-	} else {
-		tag = fmt.Sprintf(`<meta property="%s" content="%v">`, ns, content)
+// metaProperty is a property whose name is kept as a namespace and a local
+// name, so that it is only joined when needed.
+type metaProperty struct {
+	ns      string
+	prop    string
+	content string
+}
+
+func (p *metaProperty) name() string {
+	if p.prop == "" {
+		return p.ns
 	}
-	b.tags = append(b.tags, tag)
-	return b
+	return p.ns + ":" + p.prop
 }
 
-func (b *metaBuilder) Include(mb *metaBuilder) *metaBuilder {
-	b.tags = append(b.tags, mb.tags...)
+var metaBuilderPool = sync.Pool{
+	New: func() interface{} { return new(metaBuilder) },
+}
+
+// bufferPool holds the buffers the meta tags are rendered into.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// maxPooledSize is the capacity above which buffers and property slices are
+// left to the garbage collector instead of being pooled.
+const maxPooledSize = 64 << 10
+
+func newMetaBuilder(attr string) *metaBuilder {
+	mb := metaBuilderPool.Get().(*metaBuilder)
+	mb.attr = attr
+	return mb
+}
+
+// release returns the builder to the pool. It must not be used afterwards.
+func (b *metaBuilder) release() {
+	if cap(b.props) > maxPooledSize/32 {
+		return
+	}
+	for i := range b.props {
+		b.props[i] = metaProperty{}
+	}
+	b.props = b.props[:0]
+	b.base = nil
+	metaBuilderPool.Put(b)
+}
+
+func (b *metaBuilder) Add(ns, prop, content string) *metaBuilder {
+	p := metaProperty{ns: ns, prop: prop, content: content}
+	if b.base != nil && isURLProperty(p.name()) {
+		p.content = resolveURL(b.base, content)
+	}
+	b.props = append(b.props, p)
 	return b
 }
 
-func (b *metaBuilder) HTML() template.HTML {
-	return template.HTML(b.String())
+func (b *metaBuilder) AddInt(ns, prop string, content int) *metaBuilder {
+	return b.Add(ns, prop, strconv.Itoa(content))
+}
+
+// AddDuration adds a duration in whole seconds, as rounded by seconds. A
+// duration that rounds to zero or less isn't added, since it is most likely a
+// number of seconds mistaken for a time.Duration, e.g. 7200 for two hours.
+func (b *metaBuilder) AddDuration(ns, prop string, content time.Duration) *metaBuilder {
+	if seconds(content) <= 0 {
+		return b
+	}
+	return b.Add(ns, prop, strconv.FormatInt(seconds(content), 10))
+}
+
+// seconds returns d in whole seconds, rounded to the nearest second with
+// halves rounded away from zero, e.g. 1.5s is rendered as 2 and 1.4s as 1.
+func seconds(d time.Duration) int64 {
+	return int64(d.Round(time.Second) / time.Second)
+}
+
+func (b *metaBuilder) Properties() []Property {
+	props := make([]Property, len(b.props))
+	for i := range b.props {
+		props[i] = Property{Name: b.props[i].name(), Content: b.props[i].content}
+	}
+	return props
 }
 
 func (b *metaBuilder) String() string {
-	return strings.Join(b.tags, "\n")
+	buf := getBuffer()
+	defer putBuffer(buf)
+	b.render(buf)
+	return buf.String()
+}
+
+// WriteTo renders the meta tags into a pooled buffer which is written to w
+// in a single call.
+func (b *metaBuilder) WriteTo(w io.Writer) (int64, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	b.render(buf)
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+func (b *metaBuilder) render(buf *bytes.Buffer) {
+	attr := b.attr
+	if attr == "" {
+		attr = "property"
+	}
+	for index := range b.props {
+		prop := &b.props[index]
+		if index > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(`<meta `)
+		buf.WriteString(attr)
+		buf.WriteString(`="`)
+		writeEscaped(buf, prop.ns)
+		if prop.prop != "" {
+			buf.WriteByte(':')
+			writeEscaped(buf, prop.prop)
+		}
+		buf.WriteString(`" content="`)
+		writeEscaped(buf, prop.content)
+		buf.WriteString(`">`)
+	}
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// writeEscaped writes s escaped into buf. Strings made of printable ASCII
+// characters without HTML special characters, which are the vast majority,
+// are written as is.
+func writeEscaped(buf *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c < ' ', c >= 0x7f, c == '<', c == '>', c == '&', c == '\'', c == '"':
+			buf.WriteString(escape(s))
+			return
+		}
+	}
+	buf.WriteString(s)
+}
+
+// escape makes s safe to be used as a double-quoted HTML attribute value.
+// Invalid UTF-8 sequences are replaced with U+FFFD, whitespace control
+// characters are turned into spaces and the other control characters are
+// dropped before the HTML special characters get escaped.
+func escape(s string) string {
+	s = strings.ToValidUTF8(s, "\uFFFD")
+	s = strings.Map(normalize, s)
+	return html.EscapeString(s)
+}
+
+func normalize(r rune) rune {
+	switch {
+	case r == '\t', r == '\n', r == '\v', r == '\f', r == '\r':
+		return ' '
+	case unicode.IsControl(r):
+		return -1
+	}
+	return r
 }
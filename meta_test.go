@@ -0,0 +1,64 @@
+package ogp_test
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/ogp.v1"
+)
+
+var inertTag = regexp.MustCompile(`^<meta property="[^"<>]*" content="[^"<>]*">$`)
+
+func TestEscaping(t *testing.T) {
+	const hostile = "\"><script>alert('x')</script>\x00\x1b\xff\xfe<!--"
+	profile := func() *ogp.ProfileBuilder {
+		return ogp.Profile().
+			Title(hostile).
+			URL(hostile).
+			FirstName(hostile).
+			LastName(hostile).
+			Username(hostile).
+			Gender(hostile)
+	}
+	image := ogp.Image().URL(hostile).Alt(hostile).MIME(hostile)
+	tests := map[string]template.HTML{
+		"website": ogp.Website().Title(hostile).URL(hostile).Description(hostile).SiteName(hostile).Locale(hostile).Image(image).HTML(),
+		"article": ogp.Article().Title(hostile).URL(hostile).Description(hostile).Section(hostile).Tag(hostile).Author(profile()).HTML(),
+		"book":    ogp.Book().Title(hostile).URL(hostile).ISBN(hostile).Tag(hostile).Author(profile()).HTML(),
+		"profile": profile().Description(hostile).Image(image).HTML(),
+		"song":    ogp.Song().Title(hostile).URL(hostile).Album(hostile, 1, 1).Musician(profile()).HTML(),
+		"album":   ogp.Album().Title(hostile).URL(hostile).Song(hostile, 1, 1).Musician(profile()).HTML(),
+		"playlist": ogp.Playlist().Title(hostile).URL(hostile).Song(hostile, 1, 1).Creator(profile()).
+			Video(ogp.Video().URL(hostile).Alt(hostile)).HTML(),
+		"radio":   ogp.RadioStation().Title(hostile).URL(hostile).Creator(profile()).Audio(ogp.Audio().URL(hostile)).HTML(),
+		"movie":   ogp.Movie().Title(hostile).URL(hostile).Tag(hostile).Actor(profile(), hostile).Director(profile()).HTML(),
+		"tvshow":  ogp.TVShow().Title(hostile).URL(hostile).Writer(profile()).HTML(),
+		"episode": ogp.Episode().Title(hostile).URL(hostile).Series(ogp.TVShow().Title(hostile).URL(hostile)).HTML(),
+		"other":   ogp.VideoOther().Title(hostile).Determiner(hostile).URL(hostile).HTML(),
+	}
+	for name, result := range tests {
+		for _, tag := range strings.Split(string(result), "\n") {
+			if !inertTag.MatchString(tag) {
+				t.Errorf("%s: unsafe tag %q", name, tag)
+			}
+			if strings.ContainsAny(tag, "\x00\x1b") || strings.Contains(tag, "\xff") {
+				t.Errorf("%s: control characters or invalid UTF-8 left in %q", name, tag)
+			}
+		}
+	}
+}
+
+func TestEscapingNormalization(t *testing.T) {
+	result := ogp.Website().
+		Title("Tom & Jerry\r\n\tSeason\x07 1 \xc3").
+		URL("http://example.com/?a=1&b=2").
+		HTML()
+	expected := `<meta property="og:type" content="website">
+<meta property="og:title" content="Tom &amp; Jerry   Season 1 ` + "\uFFFD" + `">
+<meta property="og:url" content="http://example.com/?a=1&amp;b=2">`
+	if string(result) != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+}
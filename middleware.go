@@ -0,0 +1,659 @@
+package ogp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"html"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Policy tells Middleware what to do with the Open Graph tags already present
+// in the head of a response.
+type Policy int
+
+const (
+	// PolicyReplace removes the Open Graph tags already present, so that
+	// only the ones of the attached object remain.
+	PolicyReplace Policy = iota
+	// PolicyKeep leaves the responses already holding Open Graph tags
+	// untouched.
+	PolicyKeep
+)
+
+// maxHeadSize is the size above which Middleware stops looking for the end
+// of the head of a response and leaves it untouched.
+const maxHeadSize = 256 << 10
+
+type contextKey struct{}
+
+// attachment holds the object attached to a request by its handler.
+type attachment struct {
+	mu     sync.Mutex
+	object Object
+}
+
+// Attach attaches object to the request whose context is ctx, so that
+// Middleware injects it into the response. It must be called before the
+// handler writes the response, and does nothing outside of Middleware.
+func Attach(ctx context.Context, object Object) {
+	if a, ok := ctx.Value(contextKey{}).(*attachment); ok {
+		a.mu.Lock()
+		a.object = object
+		a.mu.Unlock()
+	}
+}
+
+// Middleware returns a handler injecting the Open Graph object attached by
+// next with Attach into its HTML responses. The tags are inserted just after
+// the `<head>` tag, whose `prefix` attribute gets the namespaces of the
+// object, and the existing Open Graph tags are handled according to policy.
+//
+// Only the head of the response is buffered, the rest being streamed as it
+// is written. Gzip-encoded responses are decoded and encoded again, while
+// responses that aren't HTML, successful or encoded otherwise are left
+// untouched.
+func Middleware(next http.Handler, policy Policy) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := &attachment{}
+		rw := &responseWriter{ResponseWriter: w, attachment: a, policy: policy, head: r.Method == http.MethodHead}
+		defer func() {
+			if v := recover(); v != nil {
+				rw.abort()
+				panic(v)
+			}
+		}()
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), contextKey{}, a)))
+		rw.close()
+	})
+}
+
+// responseWriter decides, once the response starts to be written, whether it
+// gets rewritten, in which case the body goes through a headRewriter.
+type responseWriter struct {
+	http.ResponseWriter
+	attachment *attachment
+	policy     Policy
+	// head tells whether the request is a HEAD request, whose response has
+	// no body.
+	head bool
+
+	status  int
+	decided bool
+	// out receives the body: the ResponseWriter itself, a headRewriter or
+	// the pipe to the goroutine decoding gzip-encoded bodies.
+	out      io.Writer
+	rewriter *headRewriter
+	gzip     *gzipRewriter
+}
+
+// WriteHeader records the final status of the response, written along with
+// the header once the body starts. Informational statuses, e.g. 103 Early
+// Hints, are sent right away.
+func (w *responseWriter) WriteHeader(status int) {
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.decide(p)
+	}
+	return w.out.Write(p)
+}
+
+// Flush sends the body written so far to the client, except for the head of
+// the document that is still buffered.
+func (w *responseWriter) Flush() {
+	if !w.decided {
+		w.decide(nil)
+	}
+	if w.gzip != nil {
+		w.gzip.flush()
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, e.g. to upgrade it to a
+// WebSocket, in which case the response is left to the handler.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok || w.decided {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.decided = true
+		w.out = w.ResponseWriter
+	}
+	return conn, rw, err
+}
+
+// decide chooses how the response is written given the first bytes p of its
+// body, and writes the header.
+func (w *responseWriter) decide(p []byte) {
+	w.decided = true
+	w.out = w.ResponseWriter
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(p) > 0 && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Type", http.DetectContentType(p))
+	}
+	w.attachment.mu.Lock()
+	object := w.attachment.object
+	w.attachment.mu.Unlock()
+	if object != nil && w.rewritable() {
+		header.Del("Content-Length")
+		switch strings.ToLower(header.Get("Content-Encoding")) {
+		case "", "identity":
+			w.rewriter = &headRewriter{w: w.ResponseWriter, object: object, policy: w.policy}
+			w.out = w.rewriter
+		default:
+			w.gzip = newGzipRewriter(w.ResponseWriter, object, w.policy)
+			w.out = w.gzip
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// rewritable reports whether the response is a successful HTML document
+// with a body that can be decoded.
+func (w *responseWriter) rewritable() bool {
+	if w.head || w.status < 200 || w.status >= 300 || w.status == http.StatusNoContent || w.status == http.StatusPartialContent {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil || mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return false
+	}
+	switch strings.ToLower(w.Header().Get("Content-Encoding")) {
+	case "", "identity", "gzip", "x-gzip":
+		return true
+	}
+	return false
+}
+
+// close completes the response once the handler returns.
+func (w *responseWriter) close() {
+	if !w.decided {
+		w.decide(nil)
+	}
+	if w.rewriter != nil {
+		w.rewriter.Close()
+	}
+	if w.gzip != nil {
+		w.gzip.Close()
+	}
+}
+
+// abort discards the response once the handler panicked, and stops the
+// goroutine decoding gzip-encoded bodies.
+func (w *responseWriter) abort() {
+	if w.rewriter != nil {
+		w.rewriter.done = true
+		w.rewriter.buf = nil
+	}
+	if w.gzip != nil {
+		w.gzip.pw.CloseWithError(errAborted)
+		<-w.gzip.done
+	}
+}
+
+// errAborted stops the rewriting of the body of a handler that panicked.
+var errAborted = errors.New("ogp: handler aborted")
+
+// gzipRewriter decodes a gzip-encoded body, rewrites it and encodes it again.
+// Decoding needs a reader, so it happens in a goroutine reading the body
+// written to a pipe. A body that turns out not to be gzip-encoded passes
+// through untouched.
+type gzipRewriter struct {
+	pw   *io.PipeWriter
+	done chan struct{}
+	// mu guards zw and the ResponseWriter, written by the goroutine and
+	// flushed by the handler.
+	mu sync.Mutex
+	// zw is nil until the gzip header of the body is decoded.
+	zw *gzip.Writer
+	w  http.ResponseWriter
+}
+
+func newGzipRewriter(w http.ResponseWriter, object Object, policy Policy) *gzipRewriter {
+	pr, pw := io.Pipe()
+	g := &gzipRewriter{pw: pw, done: make(chan struct{}), w: w}
+	go func() {
+		defer close(g.done)
+		r := &recordingReader{r: pr, record: true}
+		zr, err := gzip.NewReader(r)
+		switch {
+		case err == io.EOF:
+			// The body is empty.
+			err = nil
+		case err == errAborted:
+		case err != nil:
+			// The body isn't gzip-encoded after all.
+			out := lockedWriter{&g.mu, g.w}
+			if _, err = out.Write(r.buf); err == nil {
+				_, err = io.Copy(out, pr)
+			}
+		default:
+			r.record, r.buf = false, nil
+			g.mu.Lock()
+			g.zw = gzip.NewWriter(g.w)
+			g.mu.Unlock()
+			rewriter := &headRewriter{w: lockedWriter{&g.mu, g.zw}, object: object, policy: policy}
+			// A body that can't be decoded until its end is left truncated.
+			if _, err = io.Copy(rewriter, zr); err == nil {
+				rewriter.Close()
+				g.mu.Lock()
+				g.zw.Close()
+				g.mu.Unlock()
+			}
+		}
+		pr.CloseWithError(err)
+	}()
+	return g
+}
+
+func (g *gzipRewriter) Write(p []byte) (int, error) {
+	return g.pw.Write(p)
+}
+
+func (g *gzipRewriter) flush() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.zw != nil {
+		g.zw.Flush()
+	}
+	if f, ok := g.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (g *gzipRewriter) Close() error {
+	g.pw.Close()
+	<-g.done
+	return nil
+}
+
+// recordingReader records the bytes read from r while record is set.
+type recordingReader struct {
+	r      io.Reader
+	record bool
+	buf    []byte
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if r.record {
+		r.buf = append(r.buf, p[:n]...)
+	}
+	return n, err
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (w lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// headRewriter buffers an HTML document written to it until the end of its
+// head, which it writes to w rewritten, and then passes the rest through.
+type headRewriter struct {
+	w       io.Writer
+	object  Object
+	policy  Policy
+	buf     []byte
+	scanner headScanner
+	done    bool
+}
+
+func (r *headRewriter) Write(p []byte) (int, error) {
+	if r.done {
+		return r.w.Write(p)
+	}
+	r.buf = append(r.buf, p...)
+	if r.scanner.scan(r.buf, false) {
+		return len(p), r.flush()
+	}
+	if len(r.buf) > maxHeadSize {
+		r.done = true
+		_, err := r.w.Write(r.buf)
+		r.buf = nil
+		return len(p), err
+	}
+	return len(p), nil
+}
+
+// Close writes the document buffered so far, whose head never ended.
+func (r *headRewriter) Close() error {
+	if r.done {
+		return nil
+	}
+	r.scanner.scan(r.buf, true)
+	return r.flush()
+}
+
+// flush writes the buffered document, rewritten according to the scanner.
+func (r *headRewriter) flush() error {
+	s := &r.scanner
+	r.done = true
+	buf := r.buf
+	r.buf = nil
+	if s.head == nil || r.policy == PolicyKeep && len(s.metas) > 0 {
+		_, err := r.w.Write(buf)
+		return err
+	}
+	out := getBuffer()
+	defer putBuffer(out)
+	head := s.head
+	out.Write(buf[:head.start])
+	prefix := r.prefix(head)
+	if attr := head.attr("prefix"); attr != nil {
+		out.Write(buf[head.start:attr.start])
+		out.WriteString(`prefix="` + escape(prefix) + `"`)
+		out.Write(buf[attr.end:head.end])
+	} else {
+		end := head.end - 1
+		if buf[end-1] == '/' {
+			end--
+		}
+		out.Write(buf[head.start:end])
+		out.WriteString(` prefix="` + escape(prefix) + `"`)
+		out.Write(buf[end:head.end])
+	}
+	out.WriteByte('\n')
+	r.object.WriteTo(out)
+	last := head.end
+	if r.policy == PolicyReplace {
+		for _, meta := range s.metas {
+			out.Write(buf[last:meta.start])
+			last = meta.end
+			for last < len(buf) && (buf[last] == ' ' || buf[last] == '\t') {
+				last++
+			}
+			if last < len(buf) && buf[last] == '\r' {
+				last++
+			}
+			if last < len(buf) && buf[last] == '\n' {
+				last++
+			}
+		}
+	}
+	out.Write(buf[last:])
+	_, err := r.w.Write(out.Bytes())
+	return err
+}
+
+// prefix returns the `prefix` attribute of head declaring the namespaces of
+// the object in addition to the ones it already declares.
+func (r *headRewriter) prefix(head *tagScan) string {
+	var prefixes []string
+	seen := make(map[string]bool)
+	if attr := head.attr("prefix"); attr != nil {
+		for _, ns := range parsePrefix(attr.value) {
+			seen[ns.Prefix] = true
+		}
+		prefixes = append(prefixes, strings.TrimSpace(attr.value))
+	}
+	for _, ns := range r.object.Namespaces() {
+		if !seen[ns.Prefix] {
+			seen[ns.Prefix] = true
+			prefixes = append(prefixes, ns.String())
+		}
+	}
+	return strings.Join(prefixes, " ")
+}
+
+// span locates bytes in a document.
+type span struct {
+	start, end int
+}
+
+type tagScan struct {
+	span
+	name  string
+	attrs []attrScan
+}
+
+type attrScan struct {
+	span
+	name  string
+	value string
+}
+
+func (t *tagScan) attr(name string) *attrScan {
+	for i := range t.attrs {
+		if t.attrs[i].name == name {
+			return &t.attrs[i]
+		}
+	}
+	return nil
+}
+
+// headScanner locates the parts of a document rewritten by headRewriter. It
+// scans the document incrementally as it gets buffered.
+type headScanner struct {
+	// head is the `<head>` tag, or nil if the document has none.
+	head *tagScan
+	// metas are the Open Graph meta tags of the head.
+	metas []span
+	// declared are the namespaces declared by the `<html>` and `<head>`
+	// tags.
+	declared []Namespace
+	// pos is the offset where scanning resumes.
+	pos int
+	// rawEnd is the end tag of the raw text element being scanned, e.g.
+	// `</script`, whose search resumes at pos.
+	rawEnd string
+}
+
+// scan scans buf, which extends the previously scanned buffer, up to the end
+// of the head of the document and reports whether it was reached. At eof, the
+// end of buf ends the head.
+func (s *headScanner) scan(buf []byte, eof bool) bool {
+	for {
+		if s.rawEnd != "" {
+			end := indexFold(buf[s.pos:], s.rawEnd)
+			if end < 0 {
+				// The end tag may start in the last bytes.
+				if pos := len(buf) - len(s.rawEnd) + 1; pos > s.pos {
+					s.pos = pos
+				}
+				return eof
+			}
+			s.pos += end
+			s.rawEnd = ""
+		}
+		index := bytes.IndexByte(buf[s.pos:], '<')
+		if index < 0 {
+			s.pos = len(buf)
+			return eof
+		}
+		s.pos += index
+		rest := buf[s.pos:]
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			end := bytes.Index(rest[4:], []byte("-->"))
+			if end < 0 {
+				return eof
+			}
+			s.pos += 4 + end + 3
+		case bytes.HasPrefix(rest, []byte("<!")), bytes.HasPrefix(rest, []byte("<?")):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				return eof
+			}
+			s.pos += end + 1
+		case len(rest) > 2 && rest[1] == '/' && isLetter(rest[2]):
+			t, ok := scanTag(buf, s.pos+1)
+			if !ok {
+				return eof
+			}
+			if t.name == "head" {
+				return true
+			}
+			s.pos = t.end
+		case len(rest) > 1 && isLetter(rest[1]):
+			t, ok := scanTag(buf, s.pos)
+			if !ok {
+				return eof
+			}
+			switch t.name {
+			case "body":
+				return true
+			case "html":
+				s.declared = append(s.declared, t.declarations()...)
+			case "head":
+				if s.head == nil {
+					s.head = &t
+					s.declared = append(s.declared, t.declarations()...)
+				}
+			case "meta":
+				if s.head != nil && t.isProperty(s.declared) {
+					s.metas = append(s.metas, t.span)
+				}
+			}
+			if _, ok := rawText[t.name]; ok {
+				s.rawEnd = "</" + t.name
+			}
+			s.pos = t.end
+		case len(rest) < 3 && !eof:
+			return false
+		default:
+			s.pos++
+		}
+	}
+}
+
+// scanTag scans the tag starting at buf[i], right after `<` for end tags,
+// and reports whether it is complete.
+func scanTag(buf []byte, i int) (tagScan, bool) {
+	t := tagScan{span: span{start: i}}
+	j := i + 1
+	for j < len(buf) && !isSpace(buf[j]) && buf[j] != '/' && buf[j] != '>' {
+		j++
+	}
+	t.name = strings.ToLower(string(buf[i+1 : j]))
+	for {
+		for j < len(buf) && (isSpace(buf[j]) || buf[j] == '/') {
+			j++
+		}
+		if j >= len(buf) {
+			return t, false
+		}
+		if buf[j] == '>' {
+			t.end = j + 1
+			return t, true
+		}
+		attr := attrScan{span: span{start: j}}
+		for j < len(buf) && !isSpace(buf[j]) && buf[j] != '/' && buf[j] != '>' && (buf[j] != '=' || j == attr.start) {
+			j++
+		}
+		attr.name = strings.ToLower(string(buf[attr.start:j]))
+		k := j
+		for k < len(buf) && isSpace(buf[k]) {
+			k++
+		}
+		if k < len(buf) && buf[k] == '=' {
+			k++
+			for k < len(buf) && isSpace(buf[k]) {
+				k++
+			}
+			if k >= len(buf) {
+				return t, false
+			}
+			if c := buf[k]; c == '"' || c == '\'' {
+				end := bytes.IndexByte(buf[k+1:], c)
+				if end < 0 {
+					return t, false
+				}
+				attr.value = string(buf[k+1 : k+1+end])
+				j = k + 1 + end + 1
+			} else {
+				j = k
+				for j < len(buf) && !isSpace(buf[j]) && buf[j] != '>' {
+					j++
+				}
+				attr.value = string(buf[k:j])
+			}
+			attr.value = html.UnescapeString(attr.value)
+		}
+		attr.end = j
+		t.attrs = append(t.attrs, attr)
+	}
+}
+
+// declarations returns the namespaces declared by the attributes of t.
+func (t *tagScan) declarations() []Namespace {
+	tok := token{typ: startTagToken, name: t.name}
+	for _, attr := range t.attrs {
+		tok.attrs = append(tok.attrs, attribute{name: attr.name, value: attr.value})
+	}
+	return declarations(&tok)
+}
+
+// isProperty reports whether the meta tag t holds a property that Parse
+// would extract.
+func (t *tagScan) isProperty(declared []Namespace) bool {
+	attr := t.attr("property")
+	if attr == nil {
+		attr = t.attr("name")
+	}
+	return attr != nil && t.attr("content") != nil && known(strings.ToLower(strings.TrimSpace(attr.value)), declared)
+}
+
+// indexFold is like bytes.Index but ignores the case of ASCII letters. sep
+// must be lowercase and must not start with a letter.
+func indexFold(s []byte, sep string) int {
+	for i := 0; i+len(sep) <= len(s); i++ {
+		index := bytes.IndexByte(s[i:], sep[0])
+		if index < 0 {
+			return -1
+		}
+		i += index
+		if hasPrefixFold(s[i:], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasPrefixFold reports whether s begins with prefix, which is lowercase,
+// ignoring the case of ASCII letters.
+func hasPrefixFold(s []byte, prefix string) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
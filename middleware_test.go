@@ -0,0 +1,312 @@
+package ogp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<title>Example</title>
+<meta property="og:title" content="Old">
+<script>document.write("<head>")</script>
+</head>
+<body><head></body>
+</html>`
+
+func serve(t *testing.T, policy ogp.Policy, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	ogp.Middleware(handler, policy).ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/", nil))
+	return w
+}
+
+func attach(r *http.Request) {
+	ogp.Attach(r.Context(), ogp.Article().Title("Example").URL("http://example.com/"))
+}
+
+func TestMiddleware(t *testing.T) {
+	w := serve(t, ogp.PolicyReplace, func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte(page))
+	})
+	expected := `<!DOCTYPE html>
+<html>
+<head prefix="og: https://ogp.me/ns# article: https://ogp.me/ns/article#">
+<meta property="og:type" content="article">
+<meta property="og:title" content="Example">
+<meta property="og:url" content="http://example.com/">
+<title>Example</title>
+<script>document.write("<head>")</script>
+</head>
+<body><head></body>
+</html>`
+	if result := w.Body.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if result := w.Header().Get("Content-Type"); result != "text/html; charset=utf-8" {
+		t.Errorf("unexpected content type: %s", result)
+	}
+	if result := w.Header().Get("Content-Length"); result != "" {
+		t.Errorf("unexpected content length: %s", result)
+	}
+}
+
+func TestMiddlewareChunked(t *testing.T) {
+	expected := serve(t, ogp.PolicyReplace, func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Write([]byte(page))
+	}).Body.String()
+	w := serve(t, ogp.PolicyReplace, func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Header().Set("Content-Type", "text/html")
+		for i := 0; i < len(page); i++ {
+			w.Write([]byte{page[i]})
+			w.(http.Flusher).Flush()
+		}
+	})
+	if result := w.Body.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if !w.Flushed {
+		t.Error("response not flushed")
+	}
+}
+
+func TestMiddlewareGzip(t *testing.T) {
+	expected := serve(t, ogp.PolicyReplace, func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Write([]byte(page))
+	}).Body.String()
+	w := serve(t, ogp.PolicyReplace, func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(page[:20]))
+		zw.Flush()
+		w.(http.Flusher).Flush()
+		zw.Write([]byte(page[20:]))
+		zw.Close()
+	})
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(result) != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestMiddlewareRawText(t *testing.T) {
+	script := "<SCRIPT>" + strings.Repeat(`x = "<head><meta property='og:title' content='No'>";`, 1<<10) + "</ScRiPt>"
+	document := "<html><head>" + script + `<meta property="og:title" content="Old"></head></html>`
+	var result string
+	allocs := testing.AllocsPerRun(1, func() {
+		result = serve(t, ogp.PolicyReplace, func(w http.ResponseWriter, r *http.Request) {
+			attach(r)
+			w.Header().Set("Content-Type", "text/html")
+			for i := 0; i < len(document); i += 64 {
+				w.Write([]byte(document[i:minInt(len(document), i+64)]))
+			}
+		}).Body.String()
+	})
+	expected := `<html><head prefix="og: https://ogp.me/ns# article: https://ogp.me/ns/article#">
+<meta property="og:type" content="article">
+<meta property="og:title" content="Example">
+<meta property="og:url" content="http://example.com/">` + script + `</head></html>`
+	if result != expected {
+		t.Errorf("unexpected result:\n%.300s\nexpected:\n%.300s", result, expected)
+	}
+	// The raw text isn't scanned again on every write.
+	if allocs > 1000 {
+		t.Errorf("unexpected allocations: %v", allocs)
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	for _, n := range []int{5, len(page)} {
+		func() {
+			defer func() {
+				if recover() != "boom" {
+					t.Error("handler didn't panic")
+				}
+			}()
+			serve(t, ogp.PolicyReplace, func(w http.ResponseWriter, r *http.Request) {
+				attach(r)
+				w.Header().Set("Content-Type", "text/html")
+				w.Header().Set("Content-Encoding", "gzip")
+				zw := gzip.NewWriter(w)
+				zw.Write([]byte(page[:n]))
+				zw.Flush()
+				panic("boom")
+			})
+		}()
+	}
+	// The goroutines decoding the bodies are gone.
+	for i := 0; runtime.NumGoroutine() > goroutines; i++ {
+		if i == 100 {
+			t.Fatalf("unexpected goroutines: %d, expected %d", runtime.NumGoroutine(), goroutines)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMiddlewareEarlyHints(t *testing.T) {
+	server := httptest.NewServer(ogp.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Header().Set("Link", "</style.css>; rel=preload; as=style")
+		w.WriteHeader(http.StatusEarlyHints)
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(page))
+	}), ogp.PolicyReplace))
+	defer server.Close()
+	tests := []struct {
+		path     string
+		status   int
+		injected bool
+	}{
+		{"/", http.StatusOK, true},
+		{"/missing", http.StatusNotFound, false},
+	}
+	for _, test := range tests {
+		resp, err := http.Get(server.URL + test.path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		injected := strings.Contains(string(body), `<meta property="og:title" content="Example">`)
+		if resp.StatusCode != test.status || injected != test.injected {
+			t.Errorf("%s: unexpected result: %d\n%s", test.path, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestMiddlewareHijack(t *testing.T) {
+	server := httptest.NewServer(ogp.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: example\r\n\r\nhello")
+		rw.Flush()
+	}), ogp.PolicyReplace))
+	defer server.Close()
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: example\r\n\r\n"))
+	result, _ := ioutil.ReadAll(conn)
+	if !strings.HasPrefix(string(result), "HTTP/1.1 101 ") || !strings.HasSuffix(string(result), "\r\n\r\nhello") {
+		t.Errorf("unexpected result:\n%s", result)
+	}
+}
+
+func TestMiddlewarePassThrough(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"no object", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(page))
+		}},
+		{"json", func(w http.ResponseWriter, r *http.Request) {
+			attach(r)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(page))
+		}},
+		{"error", func(w http.ResponseWriter, r *http.Request) {
+			attach(r)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(page))
+		}},
+		{"corrupt gzip", func(w http.ResponseWriter, r *http.Request) {
+			attach(r)
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write([]byte(page[:20]))
+			w.(http.Flusher).Flush()
+			w.Write([]byte(page[20:]))
+		}},
+		{"brotli", func(w http.ResponseWriter, r *http.Request) {
+			attach(r)
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", "br")
+			w.Write([]byte(page))
+		}},
+	}
+	for _, test := range tests {
+		if result := serve(t, ogp.PolicyReplace, test.handler).Body.String(); result != page {
+			t.Errorf("%s: unexpected result:\n%s", test.name, result)
+		}
+	}
+}
+
+func TestMiddlewarePolicy(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Write([]byte(page))
+	}
+	if result := serve(t, ogp.PolicyKeep, handler).Body.String(); result != page {
+		t.Errorf("unexpected result:\n%s", result)
+	}
+	document := strings.Replace(page, `<meta property="og:title" content="Old">`+"\n", "", 1)
+	w := serve(t, ogp.PolicyKeep, func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Write([]byte(document))
+	})
+	if result := w.Body.String(); !strings.Contains(result, `<meta property="og:title" content="Example">`) {
+		t.Errorf("unexpected result:\n%s", result)
+	}
+}
+
+func TestMiddlewarePrefix(t *testing.T) {
+	w := serve(t, ogp.PolicyReplace, func(w http.ResponseWriter, r *http.Request) {
+		attach(r)
+		w.Write([]byte(`<html><HEAD Prefix='og: https://ogp.me/ns# acme: https://example.com/ns/acme#' /><meta property="acme:stock" content="42"></head></html>`))
+	})
+	expected := `<html><HEAD prefix="og: https://ogp.me/ns# acme: https://example.com/ns/acme# article: https://ogp.me/ns/article#" />
+<meta property="og:type" content="article">
+<meta property="og:title" content="Example">
+<meta property="og:url" content="http://example.com/"></head></html>`
+	if result := w.Body.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	object, err := ogp.Parse(bytes.NewReader(w.Body.Bytes()))
+	if err != nil || object.Type() != "article" {
+		t.Errorf("unexpected result: %v, %v", object, err)
+	}
+}
@@ -0,0 +1,77 @@
+package ogp
+
+// CheckTracks checks that album and songs agree on the position of each
+// song: every song listed by album at a given disc and track must reference
+// album back at the same position with `music:album:disc` and
+// `music:album:track`. Songs that reference no album at all, or that album
+// doesn't list, are not checked. The returned error, if any, is a
+// ValidationErrors whose paths locate the songs of the album.
+func CheckTracks(album *MusicAlbumBuilder, songs ...*MusicSongBuilder) error {
+	var v validator
+	for i, ref := range album.data.Songs {
+		for _, song := range songs {
+			if song.data.URL == ref.URL {
+				checkTrack(&v, join("", "music:song", i), "music:song", album.data.URL, ref.Disc, ref.Track, song.data.Albums)
+			}
+		}
+	}
+	return v.err()
+}
+
+// position is the disc and track of a song on an album.
+type position struct {
+	disc, track int
+}
+
+// checkTrack checks that the disc and track at which an album references a
+// song under ns agree with the positions at which the song references the
+// album, located by url, back in albums.
+func checkTrack(v *validator, path, ns, url string, disc, track int, albums []MusicAlbumRef) {
+	var back []position
+	for _, ref := range albums {
+		if ref.URL == url {
+			back = append(back, position{ref.Disc, ref.Track})
+		}
+	}
+	checkPosition(v, path, ns, position{disc, track}, back, len(albums))
+}
+
+// checkSongTrack is like checkTrack for an album referenced by a song, whose
+// position must agree with the ones at which the album lists the song back.
+func checkSongTrack(v *validator, path, ns, url string, disc, track int, songs []MusicSongRef) {
+	var back []position
+	for _, ref := range songs {
+		if ref.URL == url {
+			back = append(back, position{ref.Disc, ref.Track})
+		}
+	}
+	checkPosition(v, path, ns, position{disc, track}, back, len(songs))
+}
+
+// checkPosition reports the disagreements between p and the positions back
+// of the back-references, out of the refs references held by the referenced
+// object. An unset disc or track agrees with any other.
+func checkPosition(v *validator, path, ns string, p position, back []position, refs int) {
+	if refs == 0 {
+		return
+	}
+	if len(back) == 0 {
+		v.add(path, ns, RuleConsistency)
+		return
+	}
+	for _, b := range back {
+		if agree(p.disc, b.disc) && agree(p.track, b.track) {
+			return
+		}
+	}
+	if !agree(p.disc, back[0].disc) {
+		v.add(path, name(ns, "disc"), RuleConsistency)
+	}
+	if !agree(p.track, back[0].track) {
+		v.add(path, name(ns, "track"), RuleConsistency)
+	}
+}
+
+func agree(a, b int) bool {
+	return a == 0 || b == 0 || a == b
+}
@@ -2,138 +2,363 @@ package ogp
 
 import (
 	"html/template"
+	"io"
 	"time"
 )
 
+// MusicAlbumData holds the properties of a `music.album` object.
+type MusicAlbumData struct {
+	WebsiteData
+	ReleaseDate *DateTime      `json:"release_date,omitempty"`
+	Songs       []MusicSongRef `json:"songs,omitempty"`
+	Musicians   []ProfileData  `json:"musicians,omitempty"`
+}
+
 // MusicAlbumBuilder builds a `music.album` object.
 type MusicAlbumBuilder struct {
-	WebsiteBuilder
-	releaseDate *time.Time
-	songs       metaBuilder
-	musicians   metaBuilder
+	data MusicAlbumData
 }
 
 // Title sets the `music:title` property.
 func (b *MusicAlbumBuilder) Title(title string) *MusicAlbumBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `music:url` property.
 func (b *MusicAlbumBuilder) URL(url string) *MusicAlbumBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `music:description` property.
 func (b *MusicAlbumBuilder) Description(description string) *MusicAlbumBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `music:determiner` property.
 func (b *MusicAlbumBuilder) Determiner(determiner string) *MusicAlbumBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `music:locale` or adds a new `music:locale:alternate` property.
 func (b *MusicAlbumBuilder) Locale(locale string) *MusicAlbumBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `music:site_name` property.
 func (b *MusicAlbumBuilder) SiteName(siteName string) *MusicAlbumBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `music:image` property.
 func (b *MusicAlbumBuilder) Image(image *ImageBuilder) *MusicAlbumBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `music:video` property.
 func (b *MusicAlbumBuilder) Video(video *VideoBuilder) *MusicAlbumBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `music:audio` property.
 func (b *MusicAlbumBuilder) Audio(audio *AudioBuilder) *MusicAlbumBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *MusicAlbumBuilder) Facebook(facebook *FacebookBuilder) *MusicAlbumBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *MusicAlbumBuilder) AppLink(appLink *AppLinkBuilder) *MusicAlbumBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *MusicAlbumBuilder) Restrictions(restrictions *RestrictionsBuilder) *MusicAlbumBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
 	return b
 }
 
-// ReleaseDate sets the `music:release_date` property.
-func (b *MusicAlbumBuilder) ReleaseDate(releaseDate time.Time) *MusicAlbumBuilder {
-	b.releaseDate = &releaseDate
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *MusicAlbumBuilder) TTL(ttl time.Duration) *MusicAlbumBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *MusicAlbumBuilder) UpdatedTime(updatedTime time.Time) *MusicAlbumBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *MusicAlbumBuilder) RichAttachment(richAttachment bool) *MusicAlbumBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *MusicAlbumBuilder) Property(ns, prop, content string) *MusicAlbumBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *MusicAlbumBuilder) Namespace(prefix, uri string) *MusicAlbumBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *MusicAlbumBuilder) Defaults(defaults *Defaults) *MusicAlbumBuilder {
+	b.data.defaults = defaults
+	return b
+}
+
+// ReleaseDate sets the `music:release_date` property, either a
+// calendar date, e.g. NewDate(2020, time.May, 1), or an instant, e.g.
+// TimeOf(time.Now()).
+func (b *MusicAlbumBuilder) ReleaseDate(releaseDate DateTime) *MusicAlbumBuilder {
+	b.data.ReleaseDate = &releaseDate
 	return b
 }
 
 // Song adds a new `music:song` property.
 func (b *MusicAlbumBuilder) Song(url string, disc, track int) *MusicAlbumBuilder {
-	var mb metaBuilder
-	if url != "" {
-		mb.Add("music:song", "", url)
-	}
-	if disc > 0 {
-		mb.Add("music:song", "disc", disc)
-	}
-	if track > 0 {
-		mb.Add("music:song", "track", track)
-	}
-	b.songs.Include(&mb)
+	b.data.Songs = append(b.data.Songs, MusicSongRef{URL: url, Disc: disc, Track: track})
+	return b
+}
+
+// Track adds a new `music:song` property along with the properties of the
+// song, e.g. its title, duration and musicians. Validate checks that the
+// `music:album` references of the song agree with disc and track.
+func (b *MusicAlbumBuilder) Track(song *MusicSongBuilder, disc, track int) *MusicAlbumBuilder {
+	b.data.Songs = append(b.data.Songs, songRefOf(song, disc, track))
 	return b
 }
 
 // Musician adds a new `music:musician` property.
-func (b *MusicAlbumBuilder) Musician(musician *ProfileBuilder) *MusicAlbumBuilder {
-	b.musicians.Include(musician.meta("music:musician"))
+func (b *MusicAlbumBuilder) Musician(musician Object) *MusicAlbumBuilder {
+	b.data.Musicians = append(b.data.Musicians, profileOf(musician))
 	return b
 }
 
+// Data returns the properties of the `music.album` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *MusicAlbumBuilder) Data() *MusicAlbumData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `music.album` object.
+func (d MusicAlbumData) Builder() *MusicAlbumBuilder {
+	return &MusicAlbumBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `music.album`.
+func (b *MusicAlbumBuilder) Type() string {
+	return "music.album"
+}
+
+// Validate checks the `music.album` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *MusicAlbumBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v, "", "og")
+	return v.err()
+}
+
 // HTML renders the `music.album` object to be used in HTML templates.
 func (b *MusicAlbumBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `music.album` object as a schema.org `MusicAlbum` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *MusicAlbumBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `music.album` object as HTML markup.
+func (b *MusicAlbumBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `music.album` object, in rendering
+// order.
+func (b *MusicAlbumBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `music.album` object.
+func (b *MusicAlbumBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `music.album` object as HTML markup into w.
+func (b *MusicAlbumBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *MusicAlbumBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "music.album")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb, "og")
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *MusicAlbumData) meta(mb *metaBuilder, ns string) {
+	d.baseMeta(mb, ns, "music.album")
+	mns := ns
+	if ns == "og" {
+		mns = "music"
+	}
+	if d.ReleaseDate != nil {
+		mb.Add(mns, "release_date", d.ReleaseDate.String())
+	}
+	if ns == "og" {
+		for i := range d.Songs {
+			d.Songs[i].meta(mb, "music:song")
+		}
+	}
+	for i := range d.Musicians {
+		d.Musicians[i].meta(mb, mns+":musician")
+	}
+}
+
+func (d *MusicAlbumData) validate(v *validator, path, ns string) {
+	d.baseValidate(v, path, ns)
+	mns := ns
+	if ns == "og" {
+		mns = "music"
+		for i := range d.Songs {
+			d.Songs[i].validate(v, join(path, "music:song", i), "music:song")
+			if song := d.Songs[i].Song; song != nil {
+				checkTrack(v, join(path, "music:song", i), "music:song", d.URL, d.Songs[i].Disc, d.Songs[i].Track, song.Albums)
+			}
+		}
 	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
+	for i := range d.Musicians {
+		d.Musicians[i].validate(v, join(path, mns+":musician", i), mns+":musician")
 	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add("og", "locale", locale)
-		} else {
-			mb.Add("og", "locale:alternate", locale)
+}
+
+func (d *MusicAlbumData) linkedData() linkedData {
+	ld := d.baseLinkedData("MusicAlbum")
+	ld.set("datePublished", d.ReleaseDate)
+	ld.set("track", songsLinkedData(d.Songs))
+	ld.set("byArtist", peopleLinkedData(d.Musicians))
+	return ld
+}
+
+func (d *MusicAlbumData) decode(g *group, og, ns string) {
+	switch g.Name {
+	case ns + "release_date":
+		if t, err := ParseDateTime(g.Content); err == nil {
+			d.ReleaseDate = &t
 		}
+	case "music:song":
+		d.Songs = append(d.Songs, decodeSongRef(g))
+	case ns + "musician":
+		d.Musicians = append(d.Musicians, decodeProfile(g))
+	default:
+		d.baseDecode(g, og)
+	}
+}
+
+// MusicSongRef is a `music:song` reference of an album or a playlist. Song
+// holds the properties of the song when it is referenced with Track, in which
+// case its URL is the one of the reference.
+type MusicSongRef struct {
+	URL   string         `json:"url,omitempty"`
+	Disc  int            `json:"disc,omitempty"`
+	Track int            `json:"track,omitempty"`
+	Song  *MusicSongData `json:"song,omitempty"`
+}
+
+func (r *MusicSongRef) meta(mb *metaBuilder, ns string) {
+	if r.Song != nil {
+		r.Song.meta(mb, ns)
+	} else if r.URL != "" {
+		mb.Add(ns, "", r.URL)
 	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
+	if r.Disc > 0 {
+		mb.AddInt(ns, "disc", r.Disc)
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+	if r.Track > 0 {
+		mb.AddInt(ns, "track", r.Track)
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+}
+
+func (r *MusicSongRef) validate(v *validator, path, ns string) {
+	if r.Song != nil {
+		r.Song.validate(v, path, ns)
+	} else {
+		v.required(path, ns, r.URL)
+		v.url(path, ns, r.URL)
+	}
+	v.nonNegative(path, name(ns, "disc"), r.Disc)
+	v.nonNegative(path, name(ns, "track"), r.Track)
+}
+
+// decodeSongRef decodes a song referenced by an album or a playlist as
+// `music:song`, keeping its properties when it has more than a position.
+func decodeSongRef(g *group) MusicSongRef {
+	r := MusicSongRef{URL: g.Content, Disc: parseInt(g.prop("disc")), Track: parseInt(g.prop("track"))}
+	song := MusicSongData{WebsiteData: WebsiteData{URL: g.Content}}
+	var nested bool
+	for _, sg := range groupProperties(g.props, "image", "video", "audio", "musician") {
+		if sg.Name != "disc" && sg.Name != "track" {
+			song.decode(sg, "", "")
+			nested = true
+		}
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+	if nested {
+		r.Song = &song
 	}
-	if b.releaseDate != nil {
-		mb.Add("music", "release_date", b.releaseDate.Format(time.RFC3339))
+	return r
+}
+
+func songsLinkedData(songs []MusicSongRef) []linkedData {
+	var result []linkedData
+	for _, song := range songs {
+		ld := linkedData{"@type": "MusicRecording"}
+		if song.Song != nil {
+			ld = song.Song.linkedData()
+		}
+		ld.set("url", song.URL)
+		ld.set("position", song.Track)
+		result = append(result, ld)
 	}
-	mb.Include(&b.songs)
-	mb.Include(&b.musicians)
-	return &mb
+	return result
 }
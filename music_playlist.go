@@ -2,127 +2,265 @@ package ogp
 
 import (
 	"html/template"
+	"io"
+	"time"
 )
 
+// MusicPlaylistData holds the properties of a `music.playlist` object.
+type MusicPlaylistData struct {
+	WebsiteData
+	Songs    []MusicSongRef `json:"songs,omitempty"`
+	Creators []ProfileData  `json:"creators,omitempty"`
+}
+
 // MusicPlaylistBuilder builds a `music.playlist` object.
 type MusicPlaylistBuilder struct {
-	WebsiteBuilder
-	songs    metaBuilder
-	creators metaBuilder
+	data MusicPlaylistData
 }
 
 // Title sets the `music:title` property.
 func (b *MusicPlaylistBuilder) Title(title string) *MusicPlaylistBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `music:url` property.
 func (b *MusicPlaylistBuilder) URL(url string) *MusicPlaylistBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `music:description` property.
 func (b *MusicPlaylistBuilder) Description(description string) *MusicPlaylistBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `music:determiner` property.
 func (b *MusicPlaylistBuilder) Determiner(determiner string) *MusicPlaylistBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `music:locale` or adds a new `music:locale:alternate` property.
 func (b *MusicPlaylistBuilder) Locale(locale string) *MusicPlaylistBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `music:site_name` property.
 func (b *MusicPlaylistBuilder) SiteName(siteName string) *MusicPlaylistBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `music:image` property.
 func (b *MusicPlaylistBuilder) Image(image *ImageBuilder) *MusicPlaylistBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `music:video` property.
 func (b *MusicPlaylistBuilder) Video(video *VideoBuilder) *MusicPlaylistBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `music:audio` property.
 func (b *MusicPlaylistBuilder) Audio(audio *AudioBuilder) *MusicPlaylistBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *MusicPlaylistBuilder) Facebook(facebook *FacebookBuilder) *MusicPlaylistBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *MusicPlaylistBuilder) AppLink(appLink *AppLinkBuilder) *MusicPlaylistBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *MusicPlaylistBuilder) Restrictions(restrictions *RestrictionsBuilder) *MusicPlaylistBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *MusicPlaylistBuilder) TTL(ttl time.Duration) *MusicPlaylistBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *MusicPlaylistBuilder) UpdatedTime(updatedTime time.Time) *MusicPlaylistBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *MusicPlaylistBuilder) RichAttachment(richAttachment bool) *MusicPlaylistBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *MusicPlaylistBuilder) Property(ns, prop, content string) *MusicPlaylistBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *MusicPlaylistBuilder) Namespace(prefix, uri string) *MusicPlaylistBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *MusicPlaylistBuilder) Defaults(defaults *Defaults) *MusicPlaylistBuilder {
+	b.data.defaults = defaults
 	return b
 }
 
 // Song adds a new `music:song` property.
 func (b *MusicPlaylistBuilder) Song(url string, disc, track int) *MusicPlaylistBuilder {
-	var mb metaBuilder
-	if url != "" {
-		mb.Add("music:song", "", url)
-	}
-	if disc > 0 {
-		mb.Add("music:song", "disc", disc)
-	}
-	if track > 0 {
-		mb.Add("music:song", "track", track)
-	}
-	b.songs.Include(&mb)
+	b.data.Songs = append(b.data.Songs, MusicSongRef{URL: url, Disc: disc, Track: track})
+	return b
+}
+
+// Track adds a new `music:song` property along with the properties of the
+// song, e.g. its title, duration and musicians.
+func (b *MusicPlaylistBuilder) Track(song *MusicSongBuilder, disc, track int) *MusicPlaylistBuilder {
+	b.data.Songs = append(b.data.Songs, songRefOf(song, disc, track))
 	return b
 }
 
 // Creator adds a new `music:creator` property.
-func (b *MusicPlaylistBuilder) Creator(creator *ProfileBuilder) *MusicPlaylistBuilder {
-	b.creators.Include(creator.meta("music:creator"))
+func (b *MusicPlaylistBuilder) Creator(creator Object) *MusicPlaylistBuilder {
+	b.data.Creators = append(b.data.Creators, profileOf(creator))
 	return b
 }
 
+// Data returns the properties of the `music.playlist` object. Changes made to
+// the returned value are reflected in the builder.
+func (b *MusicPlaylistBuilder) Data() *MusicPlaylistData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `music.playlist` object.
+func (d MusicPlaylistData) Builder() *MusicPlaylistBuilder {
+	return &MusicPlaylistBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `music.playlist`.
+func (b *MusicPlaylistBuilder) Type() string {
+	return "music.playlist"
+}
+
+// Validate checks the `music.playlist` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *MusicPlaylistBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
 // HTML renders the `music.playlist` object to be used in HTML templates.
 func (b *MusicPlaylistBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `music.playlist` object as a schema.org `MusicPlaylist` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *MusicPlaylistBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `music.playlist` object as HTML markup.
+func (b *MusicPlaylistBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `music.playlist` object, in rendering
+// order.
+func (b *MusicPlaylistBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `music.playlist` object.
+func (b *MusicPlaylistBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `music.playlist` object as HTML markup into w.
+func (b *MusicPlaylistBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *MusicPlaylistBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "music.playlist")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
-	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
-	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add("og", "locale", locale)
-		} else {
-			mb.Add("og", "locale:alternate", locale)
-		}
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *MusicPlaylistData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "music.playlist")
+	for i := range d.Songs {
+		d.Songs[i].meta(mb, "music:song")
 	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
+	for i := range d.Creators {
+		d.Creators[i].meta(mb, "music:creator")
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+}
+
+func (d *MusicPlaylistData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	for i := range d.Songs {
+		d.Songs[i].validate(v, join("", "music:song", i), "music:song")
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+	for i := range d.Creators {
+		d.Creators[i].validate(v, join("", "music:creator", i), "music:creator")
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+}
+
+func (d *MusicPlaylistData) linkedData() linkedData {
+	ld := d.baseLinkedData("MusicPlaylist")
+	ld.set("track", songsLinkedData(d.Songs))
+	ld.set("creator", peopleLinkedData(d.Creators))
+	return ld
+}
+
+func (d *MusicPlaylistData) decode(g *group) {
+	switch g.Name {
+	case "music:song":
+		d.Songs = append(d.Songs, decodeSongRef(g))
+	case "music:creator":
+		d.Creators = append(d.Creators, decodeProfile(g))
+	default:
+		d.baseDecode(g, "og:")
 	}
-	mb.Include(&b.songs)
-	mb.Include(&b.creators)
-	return &mb
 }
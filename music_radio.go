@@ -1,108 +1,242 @@
 package ogp
 
-import "html/template"
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// MusicRadioStationData holds the properties of a `music.radio_station`
+// object.
+type MusicRadioStationData struct {
+	WebsiteData
+	Creators []ProfileData `json:"creators,omitempty"`
+}
 
 // MusicRadioStationBuilder builds a `music.radio_station` object.
 type MusicRadioStationBuilder struct {
-	WebsiteBuilder
-	creators metaBuilder
+	data MusicRadioStationData
 }
 
 // Title sets the `music:title` property.
 func (b *MusicRadioStationBuilder) Title(title string) *MusicRadioStationBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `music:url` property.
 func (b *MusicRadioStationBuilder) URL(url string) *MusicRadioStationBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `music:description` property.
 func (b *MusicRadioStationBuilder) Description(description string) *MusicRadioStationBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `music:determiner` property.
 func (b *MusicRadioStationBuilder) Determiner(determiner string) *MusicRadioStationBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `music:locale` or adds a new `music:locale:alternate` property.
 func (b *MusicRadioStationBuilder) Locale(locale string) *MusicRadioStationBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `music:site_name` property.
 func (b *MusicRadioStationBuilder) SiteName(siteName string) *MusicRadioStationBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `music:image` property.
 func (b *MusicRadioStationBuilder) Image(image *ImageBuilder) *MusicRadioStationBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `music:video` property.
 func (b *MusicRadioStationBuilder) Video(video *VideoBuilder) *MusicRadioStationBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `music:audio` property.
 func (b *MusicRadioStationBuilder) Audio(audio *AudioBuilder) *MusicRadioStationBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *MusicRadioStationBuilder) Facebook(facebook *FacebookBuilder) *MusicRadioStationBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *MusicRadioStationBuilder) AppLink(appLink *AppLinkBuilder) *MusicRadioStationBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *MusicRadioStationBuilder) Restrictions(restrictions *RestrictionsBuilder) *MusicRadioStationBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *MusicRadioStationBuilder) TTL(ttl time.Duration) *MusicRadioStationBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *MusicRadioStationBuilder) UpdatedTime(updatedTime time.Time) *MusicRadioStationBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *MusicRadioStationBuilder) RichAttachment(richAttachment bool) *MusicRadioStationBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *MusicRadioStationBuilder) Property(ns, prop, content string) *MusicRadioStationBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *MusicRadioStationBuilder) Namespace(prefix, uri string) *MusicRadioStationBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *MusicRadioStationBuilder) Defaults(defaults *Defaults) *MusicRadioStationBuilder {
+	b.data.defaults = defaults
 	return b
 }
 
 // Creator adds a new `music:creator` property.
-func (b *MusicRadioStationBuilder) Creator(creator *ProfileBuilder) *MusicRadioStationBuilder {
-	b.creators.Include(creator.meta("music:creator"))
+func (b *MusicRadioStationBuilder) Creator(creator Object) *MusicRadioStationBuilder {
+	b.data.Creators = append(b.data.Creators, profileOf(creator))
 	return b
 }
 
+// Data returns the properties of the `music.radio_station` object. Changes made
+// to the returned value are reflected in the builder.
+func (b *MusicRadioStationBuilder) Data() *MusicRadioStationData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `music.radio_station` object.
+func (d MusicRadioStationData) Builder() *MusicRadioStationBuilder {
+	return &MusicRadioStationBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `music.radio_station`.
+func (b *MusicRadioStationBuilder) Type() string {
+	return "music.radio_station"
+}
+
+// Validate checks the `music.radio_station` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *MusicRadioStationBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
 // HTML renders the `music.radio_station` object to be used in HTML templates.
 func (b *MusicRadioStationBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `music.radio_station` object as a schema.org `RadioStation` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *MusicRadioStationBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `music.radio_station` object as HTML markup.
+func (b *MusicRadioStationBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `music.radio_station` object, in rendering
+// order.
+func (b *MusicRadioStationBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `music.radio_station` object.
+func (b *MusicRadioStationBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `music.radio_station` object as HTML markup into w.
+func (b *MusicRadioStationBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *MusicRadioStationBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "music.radio_station")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
-	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
-	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add("og", "locale", locale)
-		} else {
-			mb.Add("og", "locale:alternate", locale)
-		}
-	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
-	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *MusicRadioStationData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "music.radio_station")
+	for i := range d.Creators {
+		d.Creators[i].meta(mb, "music:creator")
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+}
+
+func (d *MusicRadioStationData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	for i := range d.Creators {
+		d.Creators[i].validate(v, join("", "music:creator", i), "music:creator")
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+}
+
+func (d *MusicRadioStationData) linkedData() linkedData {
+	return d.baseLinkedData("RadioStation")
+}
+
+func (d *MusicRadioStationData) decode(g *group) {
+	switch g.Name {
+	case "music:creator":
+		d.Creators = append(d.Creators, decodeProfile(g))
+	default:
+		d.baseDecode(g, "og:")
 	}
-	mb.Include(&b.creators)
-	return &mb
 }
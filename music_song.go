@@ -2,137 +2,368 @@ package ogp
 
 import (
 	"html/template"
+	"io"
+	"time"
 )
 
+// MusicSongData holds the properties of a `music.song` object.
+type MusicSongData struct {
+	WebsiteData
+	Duration  *time.Duration  `json:"duration,omitempty"`
+	Albums    []MusicAlbumRef `json:"albums,omitempty"`
+	Musicians []ProfileData   `json:"musicians,omitempty"`
+}
+
 // MusicSongBuilder builds a `music.song` object.
 type MusicSongBuilder struct {
-	WebsiteBuilder
-	duration  int
-	albums    metaBuilder
-	musicians metaBuilder
+	data MusicSongData
 }
 
 // Title sets the `music:title` property.
 func (b *MusicSongBuilder) Title(title string) *MusicSongBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `music:url` property.
 func (b *MusicSongBuilder) URL(url string) *MusicSongBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `music:description` property.
 func (b *MusicSongBuilder) Description(description string) *MusicSongBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `music:determiner` property.
 func (b *MusicSongBuilder) Determiner(determiner string) *MusicSongBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `music:locale` or adds a new `music:locale:alternate` property.
 func (b *MusicSongBuilder) Locale(locale string) *MusicSongBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `music:site_name` property.
 func (b *MusicSongBuilder) SiteName(siteName string) *MusicSongBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `music:image` property.
 func (b *MusicSongBuilder) Image(image *ImageBuilder) *MusicSongBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `music:video` property.
 func (b *MusicSongBuilder) Video(video *VideoBuilder) *MusicSongBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `music:audio` property.
 func (b *MusicSongBuilder) Audio(audio *AudioBuilder) *MusicSongBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *MusicSongBuilder) Facebook(facebook *FacebookBuilder) *MusicSongBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *MusicSongBuilder) AppLink(appLink *AppLinkBuilder) *MusicSongBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *MusicSongBuilder) Restrictions(restrictions *RestrictionsBuilder) *MusicSongBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
 	return b
 }
 
-// Duration sets the `music:duration` property.
-func (b *MusicSongBuilder) Duration(duration int) *MusicSongBuilder {
-	b.duration = duration
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *MusicSongBuilder) TTL(ttl time.Duration) *MusicSongBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *MusicSongBuilder) UpdatedTime(updatedTime time.Time) *MusicSongBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *MusicSongBuilder) RichAttachment(richAttachment bool) *MusicSongBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *MusicSongBuilder) Property(ns, prop, content string) *MusicSongBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *MusicSongBuilder) Namespace(prefix, uri string) *MusicSongBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *MusicSongBuilder) Defaults(defaults *Defaults) *MusicSongBuilder {
+	b.data.defaults = defaults
+	return b
+}
+
+// Duration sets the `music:duration` property. It is rendered in whole
+// seconds, rounded to the nearest second with halves rounded away from zero.
+// A duration that rounds to zero or less, e.g. Duration(7200) rather than
+// Duration(2 * time.Hour), isn't rendered and is rejected by Validate.
+func (b *MusicSongBuilder) Duration(duration time.Duration) *MusicSongBuilder {
+	b.data.Duration = &duration
 	return b
 }
 
 // Album adds a new `music:album` property.
 func (b *MusicSongBuilder) Album(url string, disc, track int) *MusicSongBuilder {
-	var mb metaBuilder
-	if url != "" {
-		mb.Add("music:album", "", url)
-	}
-	if disc > 0 {
-		mb.Add("music:album", "disc", disc)
-	}
-	if track > 0 {
-		mb.Add("music:album", "track", track)
-	}
-	b.albums.Include(&mb)
+	b.data.Albums = append(b.data.Albums, MusicAlbumRef{URL: url, Disc: disc, Track: track})
+	return b
+}
+
+// InAlbum adds a new `music:album` property along with the properties of the
+// album, e.g. its title, release date and musicians. Validate checks that the
+// `music:song` references of the album agree with disc and track.
+func (b *MusicSongBuilder) InAlbum(album *MusicAlbumBuilder, disc, track int) *MusicSongBuilder {
+	data := album.data
+	data.defaults = nil
+	b.data.Albums = append(b.data.Albums, MusicAlbumRef{URL: data.URL, Disc: disc, Track: track, Album: &data})
 	return b
 }
 
 // Musician adds a new `music:musician` property.
-func (b *MusicSongBuilder) Musician(musician *ProfileBuilder) *MusicSongBuilder {
-	b.musicians.Include(musician.meta("music:musician"))
+func (b *MusicSongBuilder) Musician(musician Object) *MusicSongBuilder {
+	b.data.Musicians = append(b.data.Musicians, profileOf(musician))
 	return b
 }
 
+// Data returns the properties of the `music.song` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *MusicSongBuilder) Data() *MusicSongData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the `music.song`
+// object.
+func (d MusicSongData) Builder() *MusicSongBuilder {
+	return &MusicSongBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `music.song`.
+func (b *MusicSongBuilder) Type() string {
+	return "music.song"
+}
+
+// Validate checks the `music.song` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *MusicSongBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v, "", "og")
+	return v.err()
+}
+
 // HTML renders the `music.song` object to be used in HTML templates.
 func (b *MusicSongBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `music.song` object as a schema.org `MusicRecording` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *MusicSongBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `music.song` object as HTML markup.
+func (b *MusicSongBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `music.song` object, in rendering
+// order.
+func (b *MusicSongBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `music.song` object.
+func (b *MusicSongBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `music.song` object as HTML markup into w.
+func (b *MusicSongBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *MusicSongBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "music.song")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb, "og")
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *MusicSongData) meta(mb *metaBuilder, ns string) {
+	d.baseMeta(mb, ns, "music.song")
+	mns := ns
+	if ns == "og" {
+		mns = "music"
+	}
+	if d.Duration != nil {
+		mb.AddDuration(mns, "duration", *d.Duration)
+	}
+	if ns == "og" {
+		for i := range d.Albums {
+			d.Albums[i].meta(mb, "music:album")
+		}
+	}
+	for i := range d.Musicians {
+		d.Musicians[i].meta(mb, mns+":musician")
+	}
+}
+
+func (d *MusicSongData) validate(v *validator, path, ns string) {
+	d.baseValidate(v, path, ns)
+	mns := ns
+	if ns == "og" {
+		mns = "music"
+	}
+	v.positive(path, mns+":duration", d.Duration)
+	if ns == "og" {
+		for i := range d.Albums {
+			d.Albums[i].validate(v, join(path, "music:album", i), "music:album")
+			if album := d.Albums[i].Album; album != nil {
+				checkSongTrack(v, join(path, "music:album", i), "music:album", d.URL, d.Albums[i].Disc, d.Albums[i].Track, album.Songs)
+			}
+		}
 	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
+	for i := range d.Musicians {
+		d.Musicians[i].validate(v, join(path, mns+":musician", i), mns+":musician")
 	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add("og", "locale", locale)
+}
+
+func (d *MusicSongData) linkedData() linkedData {
+	ld := d.baseLinkedData("MusicRecording")
+	ld.set("duration", isoDuration(d.Duration))
+	var albums []linkedData
+	for _, album := range d.Albums {
+		if album.Album != nil {
+			albums = append(albums, album.Album.linkedData())
 		} else {
-			mb.Add("og", "locale:alternate", locale)
+			albums = append(albums, linkedData{"@type": "MusicAlbum", "url": album.URL})
 		}
 	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
+	ld.set("inAlbum", albums)
+	ld.set("byArtist", peopleLinkedData(d.Musicians))
+	return ld
+}
+
+func (d *MusicSongData) decode(g *group, og, ns string) {
+	switch g.Name {
+	case ns + "duration":
+		d.Duration = parseDuration(g.Content)
+	case "music:album":
+		d.Albums = append(d.Albums, decodeAlbumRef(g))
+	case ns + "musician":
+		d.Musicians = append(d.Musicians, decodeProfile(g))
+	default:
+		d.baseDecode(g, og)
+	}
+}
+
+// songRefOf returns a reference to song at the given position.
+func songRefOf(song *MusicSongBuilder, disc, track int) MusicSongRef {
+	data := song.data
+	data.defaults = nil
+	return MusicSongRef{URL: data.URL, Disc: disc, Track: track, Song: &data}
+}
+
+// MusicAlbumRef is a `music:album` reference of a song. Album holds the
+// properties of the album when it is referenced with InAlbum, in which case
+// its URL is the one of the reference.
+type MusicAlbumRef struct {
+	URL   string          `json:"url,omitempty"`
+	Disc  int             `json:"disc,omitempty"`
+	Track int             `json:"track,omitempty"`
+	Album *MusicAlbumData `json:"album,omitempty"`
+}
+
+func (r *MusicAlbumRef) meta(mb *metaBuilder, ns string) {
+	if r.Album != nil {
+		r.Album.meta(mb, ns)
+	} else if r.URL != "" {
+		mb.Add(ns, "", r.URL)
+	}
+	if r.Disc > 0 {
+		mb.AddInt(ns, "disc", r.Disc)
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+	if r.Track > 0 {
+		mb.AddInt(ns, "track", r.Track)
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+}
+
+func (r *MusicAlbumRef) validate(v *validator, path, ns string) {
+	if r.Album != nil {
+		r.Album.validate(v, path, ns)
+	} else {
+		v.required(path, ns, r.URL)
+		v.url(path, ns, r.URL)
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+	v.nonNegative(path, name(ns, "disc"), r.Disc)
+	v.nonNegative(path, name(ns, "track"), r.Track)
+}
+
+// decodeAlbumRef decodes an album referenced by a song as `music:album`,
+// keeping its properties when it has more than a position.
+func decodeAlbumRef(g *group) MusicAlbumRef {
+	r := MusicAlbumRef{URL: g.Content, Disc: parseInt(g.prop("disc")), Track: parseInt(g.prop("track"))}
+	album := MusicAlbumData{WebsiteData: WebsiteData{URL: g.Content}}
+	var nested bool
+	for _, sg := range groupProperties(g.props, "image", "video", "audio", "musician") {
+		if sg.Name != "disc" && sg.Name != "track" {
+			album.decode(sg, "", "")
+			nested = true
+		}
 	}
-	if b.duration > 0 {
-		mb.Add("music", "duration", b.duration)
+	if nested {
+		r.Album = &album
 	}
-	mb.Include(&b.albums)
-	mb.Include(&b.musicians)
-	return &mb
+	return r
 }
@@ -0,0 +1,117 @@
+package ogp_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestAlbumTrack(t *testing.T) {
+	album := ogp.Album().
+		Title("Album").
+		URL("http://example.com/album").
+		Image(ogp.Image().URL("http://example.com/album.jpg")).
+		Track(ogp.Song().
+			Title("Song").
+			URL("http://example.com/song").
+			Duration(215*time.Second).
+			Musician(ogp.Profile().URL("http://example.com/singer").FirstName("Jane")).
+			Album("http://example.com/album", 1, 3), 1, 3).
+		Song("http://example.com/other", 1, 4)
+	expected := `<meta property="og:type" content="music.album">
+<meta property="og:title" content="Album">
+<meta property="og:url" content="http://example.com/album">
+<meta property="og:image" content="http://example.com/album.jpg">
+<meta property="music:song" content="http://example.com/song">
+<meta property="music:song:title" content="Song">
+<meta property="music:song:duration" content="215">
+<meta property="music:song:musician" content="http://example.com/singer">
+<meta property="music:song:musician:first_name" content="Jane">
+<meta property="music:song:disc" content="1">
+<meta property="music:song:track" content="3">
+<meta property="music:song" content="http://example.com/other">
+<meta property="music:song:disc" content="1">
+<meta property="music:song:track" content="4">`
+	if result := album.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if err := album.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	object, err := ogp.Parse(strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := object.(*ogp.MusicAlbumBuilder).String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	songs := object.(*ogp.MusicAlbumBuilder).Data().Songs
+	if songs[0].Song == nil || *songs[0].Song.Duration != 215*time.Second || songs[1].Song != nil {
+		t.Errorf("unexpected songs: %+v", songs)
+	}
+}
+
+func TestSongInAlbum(t *testing.T) {
+	song := ogp.Song().
+		Title("Song").
+		URL("http://example.com/song").
+		Image(ogp.Image().URL("http://example.com/song.jpg")).
+		InAlbum(ogp.Album().Title("Album").URL("http://example.com/album").Song("http://example.com/song", 2, 1), 2, 1)
+	expected := `<meta property="og:type" content="music.song">
+<meta property="og:title" content="Song">
+<meta property="og:url" content="http://example.com/song">
+<meta property="og:image" content="http://example.com/song.jpg">
+<meta property="music:album" content="http://example.com/album">
+<meta property="music:album:title" content="Album">
+<meta property="music:album:disc" content="2">
+<meta property="music:album:track" content="1">`
+	if result := song.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if err := song.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	object, err := ogp.Parse(strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := object.(*ogp.MusicSongBuilder).Data().Albums[0].Album; result == nil || result.Title != "Album" {
+		t.Errorf("unexpected album: %+v", result)
+	}
+}
+
+func TestTrackConsistency(t *testing.T) {
+	image := ogp.Image().URL("http://example.com/album.jpg")
+	song := ogp.Song().URL("http://example.com/song").Album("http://example.com/album", 1, 3)
+	album := ogp.Album().Title("Album").URL("http://example.com/album").Image(image).
+		Track(song, 2, 4).
+		Track(ogp.Song().URL("http://example.com/single").Album("http://example.com/other", 1, 1), 1, 5).
+		Track(ogp.Song().URL("http://example.com/unreferenced"), 1, 6)
+	expected := `ogp: music:song[0]: music:song:disc disagrees with the referenced object
+ogp: music:song[0]: music:song:track disagrees with the referenced object
+ogp: music:song[1]: music:song disagrees with the referenced object`
+	if err := album.Validate(); err == nil || err.Error() != expected {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	flat := ogp.Album().URL("http://example.com/album").
+		Song("http://example.com/song", 1, 3).
+		Song("http://example.com/b-side", 1, 4)
+	if err := ogp.CheckTracks(flat, song, ogp.Song().URL("http://example.com/b-side").Album("http://example.com/album", 0, 4)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := ogp.CheckTracks(flat, ogp.Song().URL("http://example.com/b-side").Album("http://example.com/album", 1, 5))
+	errs, ok := err.(ogp.ValidationErrors)
+	if !ok || !reflect.DeepEqual(errs, ogp.ValidationErrors{{Path: "music:song[1]", Property: "music:song:track", Rule: ogp.RuleConsistency}}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	mismatch := ogp.Song().Title("Song").URL("http://example.com/song").Image(image).
+		InAlbum(ogp.Album().URL("http://example.com/album").Song("http://example.com/song", 1, 2), 1, 3)
+	if err := mismatch.Validate(); err == nil || err.Error() != "ogp: music:album[0]: music:album:track disagrees with the referenced object" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package ogp
+
+import (
+	"html/template"
+	"strings"
+)
+
+// Namespace is a prefix declared in the `prefix` attribute of a document,
+// e.g. `og: https://ogp.me/ns#`.
+type Namespace struct {
+	Prefix string `json:"prefix"`
+	URI    string `json:"uri"`
+}
+
+func (n Namespace) String() string {
+	return n.Prefix + ": " + n.URI
+}
+
+// knownNamespaces maps the prefixes of the specification to their URIs.
+var knownNamespaces = map[string]string{
+	"og":       "https://ogp.me/ns#",
+	"website":  "https://ogp.me/ns/website#",
+	"article":  "https://ogp.me/ns/article#",
+	"book":     "https://ogp.me/ns/book#",
+	"profile":  "https://ogp.me/ns/profile#",
+	"music":    "https://ogp.me/ns/music#",
+	"video":    "https://ogp.me/ns/video#",
+	"product":  "https://ogp.me/ns/product#",
+	"place":    "https://ogp.me/ns/place#",
+	"business": "https://ogp.me/ns/business#",
+	"fb":       "https://ogp.me/ns/fb#",
+	"al":       "http://applinks.org/schema/",
+}
+
+// profileRefs lists the properties referencing profiles, whose structured
+// properties come from the `profile` namespace. The references may be nested
+// in other structured properties, e.g. `music:song:musician` for the
+// musician of a track.
+var profileRefs = []string{
+	"article:author",
+	"book:author",
+	"music:musician",
+	"music:creator",
+	"video:actor",
+	"video:director",
+	"video:writer",
+}
+
+var profileProps = []string{"first_name", "last_name", "username", "gender"}
+
+// namespacesOf returns the namespaces used by an object of type typ with the
+// given properties: `og`, the namespace of the type and the namespaces of
+// the properties, in order of appearance, followed by the other registered
+// namespaces.
+func namespacesOf(typ string, props []Property, registered []Namespace) []Namespace {
+	var result []Namespace
+	seen := make(map[string]bool)
+	add := func(prefix string) {
+		if seen[prefix] {
+			return
+		}
+		seen[prefix] = true
+		if uri := namespaceURI(prefix, registered); uri != "" {
+			result = append(result, Namespace{Prefix: prefix, URI: uri})
+		}
+	}
+	add("og")
+	add(strings.SplitN(typ, ".", 2)[0])
+	for _, prop := range props {
+		add(strings.SplitN(prop.Name, ":", 2)[0])
+		if !seen["profile"] && isProfileProperty(prop.Name) {
+			add("profile")
+		}
+	}
+	for _, ns := range registered {
+		add(ns.Prefix)
+	}
+	return result
+}
+
+// namespaceURI returns the URI of the namespace with the given prefix, looked
+// up in the registered namespaces first, or an empty string for unknown
+// namespaces.
+func namespaceURI(prefix string, registered []Namespace) string {
+	for _, ns := range registered {
+		if ns.Prefix == prefix {
+			return ns.URI
+		}
+	}
+	return knownNamespaces[prefix]
+}
+
+// parsePrefix parses the value of a `prefix` attribute, made of `prefix: URI`
+// pairs separated by white spaces.
+func parsePrefix(s string) []Namespace {
+	var result []Namespace
+	fields := strings.Fields(s)
+	for i := 0; i+1 < len(fields); i++ {
+		if !strings.HasSuffix(fields[i], ":") || len(fields[i]) == 1 {
+			continue
+		}
+		prefix := strings.ToLower(strings.TrimSuffix(fields[i], ":"))
+		result = append(result, Namespace{Prefix: prefix, URI: fields[i+1]})
+		i++
+	}
+	return result
+}
+
+func isProfileProperty(name string) bool {
+	parts := strings.Split(name, ":")
+	if len(parts) < 3 {
+		return false
+	}
+	ref := parts[0] + ":" + parts[len(parts)-2]
+	return containsString(profileRefs, ref) && containsString(profileProps, parts[len(parts)-1])
+}
+
+// Prefix returns the value of the `prefix` attribute declaring the
+// namespaces used by the objects, e.g.
+// `og: https://ogp.me/ns# article: https://ogp.me/ns/article#`.
+func Prefix(objects ...Object) string {
+	var prefixes []string
+	seen := make(map[string]bool)
+	for _, object := range objects {
+		for _, ns := range object.Namespaces() {
+			if !seen[ns.Prefix] {
+				seen[ns.Prefix] = true
+				prefixes = append(prefixes, ns.String())
+			}
+		}
+	}
+	return strings.Join(prefixes, " ")
+}
+
+// Head renders the opening `<head>` tag with the `prefix` attribute declaring
+// the namespaces used by the objects.
+func Head(objects ...Object) template.HTML {
+	return template.HTML(`<head prefix="` + escape(Prefix(objects...)) + `">`)
+}
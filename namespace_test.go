@@ -0,0 +1,110 @@
+package ogp_test
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestNamespaces(t *testing.T) {
+	og := ogp.Namespace{Prefix: "og", URI: "https://ogp.me/ns#"}
+	profile := ogp.Namespace{Prefix: "profile", URI: "https://ogp.me/ns/profile#"}
+	music := ogp.Namespace{Prefix: "music", URI: "https://ogp.me/ns/music#"}
+	video := ogp.Namespace{Prefix: "video", URI: "https://ogp.me/ns/video#"}
+	website := ogp.Namespace{Prefix: "website", URI: "https://ogp.me/ns/website#"}
+	al := ogp.Namespace{Prefix: "al", URI: "http://applinks.org/schema/"}
+	tests := []struct {
+		object   ogp.Object
+		expected []ogp.Namespace
+	}{
+		{
+			object:   ogp.Profile().Title("John Smith").FirstName("John"),
+			expected: []ogp.Namespace{og, profile},
+		},
+		{
+			object:   ogp.Song().Title("Song").Musician(ogp.Profile().URL("http://example.com/singer")),
+			expected: []ogp.Namespace{og, music},
+		},
+		{
+			object:   ogp.Song().Title("Song").Musician(ogp.Profile().URL("http://example.com/singer").LastName("Doe")),
+			expected: []ogp.Namespace{og, music, profile},
+		},
+		{
+			object:   ogp.Episode().Title("Episode").Actor(ogp.Profile().Username("jdoe"), "Hero"),
+			expected: []ogp.Namespace{og, video, profile},
+		},
+		{
+			object: ogp.Album().Title("Album").Track(ogp.Song().
+				Title("Song").
+				URL("http://example.com/song").
+				Musician(ogp.Profile().URL("http://example.com/singer").FirstName("Jane")), 1, 1),
+			expected: []ogp.Namespace{og, music, profile},
+		},
+		{
+			object:   ogp.Website().Title("Shop").AppLink(ogp.AppLink().IOS("12345", "example://shop", "Shop")),
+			expected: []ogp.Namespace{og, website, al},
+		},
+	}
+	for _, test := range tests {
+		if result := test.object.Namespaces(); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s: unexpected namespaces: %v", test.object.Type(), result)
+		}
+	}
+}
+
+func TestPrefixMultipleObjects(t *testing.T) {
+	result := ogp.Prefix(ogp.Movie(), ogp.Book(), ogp.Episode())
+	expected := "og: https://ogp.me/ns# video: https://ogp.me/ns/video# book: https://ogp.me/ns/book#"
+	if result != expected {
+		t.Errorf("unexpected prefix: %s", result)
+	}
+}
+
+func TestPrefixAppLink(t *testing.T) {
+	website := ogp.Website().
+		Title("Shop").
+		URL("http://example.com/shop").
+		Image(ogp.Image().URL("http://example.com/shop.png")).
+		AppLink(ogp.AppLink().IOS("12345", "example://shop", "Shop")).
+		Property("al", "windows:app_id", "42")
+	expected := "og: https://ogp.me/ns# website: https://ogp.me/ns/website# al: http://applinks.org/schema/"
+	if result := ogp.Prefix(website); result != expected {
+		t.Errorf("unexpected prefix: %s", result)
+	}
+	if err := website.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCustomProperties(t *testing.T) {
+	acme := ogp.Namespace{Prefix: "acme", URI: "https://example.com/ns/acme#"}
+	website := ogp.Website().
+		Title("Shop").
+		URL("http://example.com/shop").
+		Image(ogp.Image().URL("http://example.com/shop.png")).
+		Property("acme", "stock", "42").
+		Property("og", "ttl", "345600").
+		Property("acme", "warehouse", "Lyon")
+	if err := website.Validate(); err == nil || err.Error() != "ogp: acme:stock has an undeclared namespace\nogp: acme:warehouse has an undeclared namespace" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	website.Namespace(acme.Prefix, acme.URI)
+	if err := website.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := `<meta property="og:type" content="website">
+<meta property="og:title" content="Shop">
+<meta property="og:url" content="http://example.com/shop">
+<meta property="og:image" content="http://example.com/shop.png">
+<meta property="acme:stock" content="42">
+<meta property="og:ttl" content="345600">
+<meta property="acme:warehouse" content="Lyon">`
+	if result := website.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	namespaces := []ogp.Namespace{{Prefix: "og", URI: "https://ogp.me/ns#"}, {Prefix: "website", URI: "https://ogp.me/ns/website#"}, acme}
+	if result := website.Namespaces(); !reflect.DeepEqual(result, namespaces) {
+		t.Errorf("unexpected namespaces: %v", result)
+	}
+}
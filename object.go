@@ -0,0 +1,84 @@
+package ogp
+
+import (
+	"html/template"
+	"io"
+)
+
+// Object is an Open Graph object, built by any of the builders of this
+// package.
+type Object interface {
+	// Type returns the `og:type` of the object, e.g. `video.movie`.
+	Type() string
+	// HTML renders the object to be used in HTML templates.
+	HTML() template.HTML
+	// String renders the object as HTML markup.
+	String() string
+	// Properties returns the properties of the object, in rendering order.
+	Properties() []Property
+	// Namespaces returns the namespaces used by the object, to be declared
+	// in the `prefix` attribute of the document.
+	Namespaces() []Namespace
+	// WriteTo renders the object as HTML markup into w.
+	WriteTo(w io.Writer) (int64, error)
+	// JSONLD renders the object as schema.org JSON-LD, to be used in HTML
+	// templates.
+	JSONLD() template.HTML
+	// Validate checks the object against the rules of the specification.
+	Validate() error
+}
+
+// Property is a single Open Graph property, e.g. `og:title`.
+type Property struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+var (
+	_ Object = (*WebsiteBuilder)(nil)
+	_ Object = (*ArticleBuilder)(nil)
+	_ Object = (*BookBuilder)(nil)
+	_ Object = (*ProfileBuilder)(nil)
+	_ Object = (*MusicSongBuilder)(nil)
+	_ Object = (*MusicAlbumBuilder)(nil)
+	_ Object = (*MusicPlaylistBuilder)(nil)
+	_ Object = (*MusicRadioStationBuilder)(nil)
+	_ Object = (*VideoMovieBuilder)(nil)
+	_ Object = (*VideoTVShowBuilder)(nil)
+	_ Object = (*VideoEpisodeBuilder)(nil)
+	_ Object = (*VideoOtherBuilder)(nil)
+	_ Object = (*ProductBuilder)(nil)
+	_ Object = (*ProductGroupBuilder)(nil)
+	_ Object = (*PlaceBuilder)(nil)
+	_ Object = (*BusinessBuilder)(nil)
+)
+
+// profileOf returns the properties of o as a profile, so that any object can
+// be referenced where a profile is expected.
+func profileOf(o Object) ProfileData {
+	if b, ok := o.(*ProfileBuilder); ok {
+		d := b.data
+		d.defaults = nil
+		return d
+	}
+	var d ProfileData
+	for _, g := range groupProperties(o.Properties(), "og:image", "og:video", "og:audio") {
+		d.decode(g, "og:", "profile:")
+	}
+	return d
+}
+
+// tvShowOf returns the properties of o as a TV show, so that any object can be
+// referenced where a TV show is expected.
+func tvShowOf(o Object) VideoTVShowData {
+	if b, ok := o.(*VideoTVShowBuilder); ok {
+		d := b.data
+		d.defaults = nil
+		return d
+	}
+	var d VideoTVShowData
+	for _, g := range groupProperties(o.Properties(), "og:image", "og:video", "og:audio", "video:actor", "video:director", "video:writer") {
+		d.decode(g, "og:", "video:")
+	}
+	return d
+}
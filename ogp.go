@@ -60,6 +60,31 @@ func VideoOther() *VideoOtherBuilder {
 	return &VideoOtherBuilder{}
 }
 
+// Product is the convenient way for creating a ProductBuilder.
+func Product() *ProductBuilder {
+	return &ProductBuilder{}
+}
+
+// ProductGroup is the convenient way for creating a ProductGroupBuilder.
+func ProductGroup() *ProductGroupBuilder {
+	return &ProductGroupBuilder{}
+}
+
+// Place is the convenient way for creating a PlaceBuilder.
+func Place() *PlaceBuilder {
+	return &PlaceBuilder{}
+}
+
+// Business is the convenient way for creating a BusinessBuilder.
+func Business() *BusinessBuilder {
+	return &BusinessBuilder{}
+}
+
+// Contact is the convenient way for creating a ContactBuilder.
+func Contact() *ContactBuilder {
+	return &ContactBuilder{}
+}
+
 // Image is the convrnient way for creating an ImageBuilder.
 func Image() *ImageBuilder {
 	return &ImageBuilder{}
@@ -74,3 +99,24 @@ func Video() *VideoBuilder {
 func Audio() *AudioBuilder {
 	return &AudioBuilder{}
 }
+
+// Facebook is the convenient way for creating a FacebookBuilder.
+func Facebook() *FacebookBuilder {
+	return &FacebookBuilder{}
+}
+
+// Restrictions is the convenient way for creating a RestrictionsBuilder.
+func Restrictions() *RestrictionsBuilder {
+	return &RestrictionsBuilder{}
+}
+
+// AppLink is the convenient way for creating an AppLinkBuilder.
+func AppLink() *AppLinkBuilder {
+	return &AppLinkBuilder{}
+}
+
+// TwitterCard is the convenient way for creating a TwitterCardBuilder for the
+// given object.
+func TwitterCard(object Object) *TwitterCardBuilder {
+	return &TwitterCardBuilder{object: object}
+}
@@ -2,6 +2,8 @@ package ogp_test
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"gopkg.in/ogp.v1"
 )
@@ -73,3 +75,135 @@ func ExampleProfile() {
 	// <meta property="profile:last_name" content="Smith">
 	// <meta property="profile:username" content="jsmith">
 }
+
+func ExampleParse() {
+	document := `<html prefix="og: https://ogp.me/ns#">
+<head>
+<title>The Rock (1996)</title>
+<meta property="og:title" content="The Rock">
+<meta property="og:type" content="video.movie">
+<meta property="og:url" content="https://www.imdb.com/title/tt0117500/">
+<meta property="og:image" content="https://ia.media-imdb.com/images/rock.jpg">
+</head>
+</html>`
+	object, err := ogp.Parse(strings.NewReader(document))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%T\n", object)
+	// Output:
+	// *ogp.VideoMovieBuilder
+}
+
+func ExampleArticleBuilder_Data() {
+	article := ogp.Article().
+		Title("How to Train Your Dragons").
+		URL("http://example.com/article/how-to-train-your-dragon").
+		Author(ogp.Profile().URL("http://example.com/profile/dragon-master").FirstName("Hiccup"))
+	data := article.Data()
+	fmt.Println(data.Title)
+	fmt.Println(data.Authors[0].FirstName)
+	// Output:
+	// How to Train Your Dragons
+	// Hiccup
+}
+
+func ExampleWebsiteData_Builder() {
+	data := ogp.WebsiteData{
+		Title:  "Example",
+		URL:    "http://example.com",
+		Images: []ogp.ImageData{{URL: "http://example.com/social.jpg", Width: 1200}},
+	}
+	fmt.Println(data.Builder().HTML())
+	// Output:
+	// <meta property="og:type" content="website">
+	// <meta property="og:title" content="Example">
+	// <meta property="og:url" content="http://example.com">
+	// <meta property="og:image" content="http://example.com/social.jpg">
+	// <meta property="og:image:width" content="1200">
+}
+
+func ExampleObject() {
+	render := func(object ogp.Object) {
+		fmt.Println(object.Type())
+		object.WriteTo(os.Stdout)
+		fmt.Println()
+	}
+	render(ogp.Episode().
+		Title("Pilot").
+		URL("http://example.com/show/pilot").
+		Series(ogp.Website().Title("The Show").URL("http://example.com/show")))
+	// Output:
+	// video.episode
+	// <meta property="og:type" content="video.episode">
+	// <meta property="og:title" content="Pilot">
+	// <meta property="og:url" content="http://example.com/show/pilot">
+	// <meta property="video:series" content="http://example.com/show">
+	// <meta property="video:series:title" content="The Show">
+}
+
+func ExampleArticleBuilder_Validate() {
+	err := ogp.Article().
+		Title("How to Train Your Dragons").
+		Author(ogp.Profile().FirstName("Hiccup")).
+		Validate()
+	fmt.Println(err)
+	// Output:
+	// ogp: og:url is required
+	// ogp: og:image is required
+	// ogp: article:author[0]: article:author is required
+}
+
+func ExampleTwitterCard() {
+	movie := ogp.Movie().
+		Title("The Rock").
+		URL("https://example.com/movie/the-rock").
+		Image(ogp.Image().URL("https://example.com/the-rock.jpg").Alt("Poster of The Rock")).
+		Video(ogp.Video().URL("https://example.com/player/the-rock").Width(1280).Height(720))
+	result := ogp.TwitterCard(movie).
+		Site("@example").
+		Title("The Rock (1996)").
+		HTML()
+	fmt.Println(result)
+	// Output:
+	// <meta name="twitter:card" content="player">
+	// <meta name="twitter:site" content="@example">
+	// <meta name="twitter:title" content="The Rock (1996)">
+	// <meta name="twitter:image:alt" content="Poster of The Rock">
+	// <meta name="twitter:player" content="https://example.com/player/the-rock">
+	// <meta name="twitter:player:width" content="1280">
+	// <meta name="twitter:player:height" content="720">
+}
+
+func ExampleDefaults() {
+	site := &ogp.Defaults{
+		SiteName: "Acme",
+		Locales:  []string{"en_US"},
+		Images:   []ogp.ImageData{{URL: "https://example.com/logo.png"}},
+	}
+	result := ogp.Article().
+		Title("Hello").
+		URL("https://example.com/hello").
+		Defaults(site).
+		HTML()
+	fmt.Println(result)
+	// Output:
+	// <meta property="og:type" content="article">
+	// <meta property="og:title" content="Hello">
+	// <meta property="og:url" content="https://example.com/hello">
+	// <meta property="og:locale" content="en_US">
+	// <meta property="og:site_name" content="Acme">
+	// <meta property="og:image" content="https://example.com/logo.png">
+}
+
+func ExamplePrefix() {
+	article := ogp.Article().
+		Title("How to Train Your Dragons").
+		URL("http://example.com/article/how-to-train-your-dragon").
+		Author(ogp.Profile().URL("http://example.com/profile/dragon-master").FirstName("Hiccup"))
+	fmt.Println(ogp.Prefix(article))
+	fmt.Println(ogp.Head(ogp.Website()))
+	// Output:
+	// og: https://ogp.me/ns# article: https://ogp.me/ns/article# profile: https://ogp.me/ns/profile#
+	// <head prefix="og: https://ogp.me/ns# website: https://ogp.me/ns/website#">
+}
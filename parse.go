@@ -0,0 +1,379 @@
+package ogp
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoProperties is returned by Parse when the document does not contain any
+// Open Graph property.
+var ErrNoProperties = errors.New("ogp: no Open Graph properties found")
+
+// namespaces lists the prefixes of the properties that Parse understands.
+var namespaces = []string{"og", "article", "book", "profile", "music", "video", "product", "place", "business", "fb", "al"}
+
+// Parse reads an HTML document from r and returns the Open Graph object it
+// describes. The concrete type of the object depends on the `og:type`
+// property, e.g. `article` is returned as an *ArticleBuilder. Unknown types
+// fall back to *WebsiteBuilder, as the specification requires.
+//
+// The namespaces declared by the `prefix` attribute of the `<html>` or
+// `<head>` element are registered on the object, and the properties that its
+// type doesn't model are kept as custom properties.
+func Parse(r io.Reader) (Object, error) {
+	var p Parser
+	return p.Parse(r)
+}
+
+// Parser parses Open Graph objects with options. The zero value parses as
+// Parse does.
+type Parser struct {
+	// Fallback fills in the properties missing from the Open Graph
+	// properties of the document from its other metadata: the `<title>`,
+	// `<meta name="description">`, `<link rel="canonical">` and `<html
+	// lang>` elements, the `twitter:*` properties, the schema.org JSON-LD
+	// and the largest `<img>` element. The source of each property filled
+	// in is recorded in the Sources of the data of the object.
+	Fallback bool
+	// Base is the URL of the document, which the relative URLs of the
+	// properties are resolved against, as is the `<base href>` of the
+	// document. The URLs are then normalized: their scheme and host are
+	// lowercased, and default ports are dropped. The URLs are left as is
+	// if there is no base.
+	Base *url.URL
+}
+
+// Parse reads an HTML document from r and returns the Open Graph object it
+// describes, as the Parse function does.
+func (p *Parser) Parse(r io.Reader) (Object, error) {
+	object, _, err := p.parse(r, false)
+	return object, err
+}
+
+// parse parses the document read from r, stopping at the end of its head if
+// head is true. The object is returned along with its shared properties.
+func (p *Parser) parse(r io.Reader, head bool) (Object, *WebsiteData, error) {
+	var fb *fallback
+	if p.Fallback {
+		fb = &fallback{}
+	}
+	props, declared, href, err := extract(r, head, fb)
+	if err != nil {
+		return nil, nil, err
+	}
+	var sources map[string]Source
+	if fb != nil {
+		props, sources = fb.fill(props)
+	}
+	resolveProperties(props, p.base(href))
+	if len(props) == 0 {
+		return nil, nil, ErrNoProperties
+	}
+	object, data := decode(props)
+	for _, ns := range declared {
+		if knownNamespaces[ns.Prefix] != ns.URI {
+			data.Namespaces = append(data.Namespaces, ns)
+		}
+	}
+	if len(sources) > 0 {
+		data.Sources = sources
+	}
+	return object, data, nil
+}
+
+// base returns the URL the URLs of the document are resolved against, given
+// the `<base href>` of the document, if any.
+func (p *Parser) base(href string) *url.URL {
+	if href == "" {
+		return p.Base
+	}
+	u, err := url.Parse(href)
+	switch {
+	case err != nil:
+		return p.Base
+	case p.Base != nil:
+		return p.Base.ResolveReference(u)
+	case u.IsAbs():
+		return u
+	}
+	return nil
+}
+
+// extract collects the Open Graph properties from the `<meta>` elements of
+// the document, in document order, together with the namespaces declared by
+// the document and its `<base href>`. If head is true, it stops reading at
+// the end of the head of the document. The other metadata of the document
+// is collected into fb, if not nil.
+func extract(r io.Reader, head bool, fb *fallback) ([]Property, []Namespace, string, error) {
+	var props []Property
+	var declared []Namespace
+	var base string
+	z := newTokenizer(r)
+	for {
+		t, err := z.next()
+		if err == io.EOF {
+			return props, declared, base, nil
+		} else if err != nil {
+			return nil, nil, "", err
+		}
+		if head && (t.typ == endTagToken && t.name == "head" || t.typ == startTagToken && t.name == "body") {
+			return props, declared, base, nil
+		}
+		if fb != nil {
+			fb.scan(&t)
+		}
+		if t.typ != startTagToken {
+			continue
+		}
+		if t.name == "html" || t.name == "head" {
+			declared = append(declared, declarations(&t)...)
+			continue
+		}
+		if href, ok := t.attr("href"); ok && t.name == "base" && base == "" {
+			base = strings.TrimSpace(href)
+			continue
+		}
+		if t.name != "meta" {
+			continue
+		}
+		name, ok := t.attr("property")
+		if !ok {
+			name, _ = t.attr("name")
+		}
+		content, ok := t.attr("content")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || !known(name, declared) {
+			continue
+		}
+		props = append(props, Property{Name: name, Content: strings.TrimSpace(content)})
+	}
+}
+
+// declarations returns the namespaces declared by the `prefix` and `xmlns:*`
+// attributes of t.
+func declarations(t *token) []Namespace {
+	var result []Namespace
+	for _, attr := range t.attrs {
+		switch {
+		case attr.name == "prefix":
+			result = append(result, parsePrefix(attr.value)...)
+		case strings.HasPrefix(attr.name, "xmlns:") && len(attr.name) > len("xmlns:"):
+			result = append(result, Namespace{Prefix: attr.name[len("xmlns:"):], URI: strings.TrimSpace(attr.value)})
+		}
+	}
+	return result
+}
+
+func known(name string, declared []Namespace) bool {
+	index := strings.IndexByte(name, ':')
+	if index < 0 {
+		return false
+	}
+	for _, ns := range namespaces {
+		if name[:index] == ns {
+			return true
+		}
+	}
+	for _, ns := range declared {
+		if name[:index] == ns.Prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// group is a property together with its structured properties, e.g.
+// `og:image` with `og:image:width` and `og:image:height`. The names of the
+// structured properties are relative to the group, e.g. `width`.
+type group struct {
+	Property
+	props []Property
+}
+
+// prop returns the content of the first structured property with the given
+// name.
+func (g *group) prop(name string) string {
+	for _, p := range g.props {
+		if p.Name == name {
+			return p.Content
+		}
+	}
+	return ""
+}
+
+// groupProperties attaches structured properties to the closest preceding
+// property among roots. A `url` structured property either sets the URL of
+// its root or, when the root already has one, starts a new root.
+func groupProperties(props []Property, roots ...string) []*group {
+	var groups []*group
+	last := make(map[string]*group)
+	for _, p := range props {
+		root := rootOf(p.Name, roots)
+		if root == "" {
+			groups = append(groups, &group{Property: p})
+			continue
+		}
+		g := last[root]
+		name := strings.TrimPrefix(strings.TrimPrefix(p.Name, root), ":")
+		if name == "" || name == "url" {
+			if g == nil || g.Content != "" || name == "" {
+				g = &group{Property: Property{Name: root}}
+				groups = append(groups, g)
+				last[root] = g
+			}
+			g.Content = p.Content
+			continue
+		}
+		if g == nil {
+			g = &group{Property: Property{Name: root}}
+			groups = append(groups, g)
+			last[root] = g
+		}
+		g.props = append(g.props, Property{Name: name, Content: p.Content})
+	}
+	return groups
+}
+
+func rootOf(name string, roots []string) string {
+	var result string
+	for _, root := range roots {
+		if (name == root || strings.HasPrefix(name, root+":")) && len(root) > len(result) {
+			result = root
+		}
+	}
+	return result
+}
+
+// decode builds the object described by props, returned along with its
+// shared properties.
+func decode(props []Property) (Object, *WebsiteData) {
+	var typ string
+	for _, p := range props {
+		if p.Name == "og:type" {
+			typ = p.Content
+			break
+		}
+	}
+	roots := []string{"og:image", "og:video", "og:audio"}
+	switch typ {
+	case "article":
+		b := Article()
+		for _, g := range groupProperties(props, append(roots, "article:author")...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "book":
+		b := Book()
+		for _, g := range groupProperties(props, append(roots, "book:author")...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "profile":
+		b := Profile()
+		for _, g := range groupProperties(props, roots...) {
+			b.data.decode(g, "og:", "profile:")
+		}
+		return b, &b.data.WebsiteData
+	case "music.song":
+		b := Song()
+		for _, g := range groupProperties(props, append(roots, "music:album", "music:musician")...) {
+			b.data.decode(g, "og:", "music:")
+		}
+		return b, &b.data.WebsiteData
+	case "music.album":
+		b := Album()
+		for _, g := range groupProperties(props, append(roots, "music:song", "music:musician")...) {
+			b.data.decode(g, "og:", "music:")
+		}
+		return b, &b.data.WebsiteData
+	case "music.playlist":
+		b := Playlist()
+		for _, g := range groupProperties(props, append(roots, "music:song", "music:creator")...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "music.radio_station":
+		b := RadioStation()
+		for _, g := range groupProperties(props, append(roots, "music:creator")...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "video.movie":
+		b := Movie()
+		for _, g := range groupProperties(props, append(roots, "video:actor", "video:director", "video:writer")...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "video.tv_show":
+		b := TVShow()
+		for _, g := range groupProperties(props, append(roots, "video:actor", "video:director", "video:writer")...) {
+			b.data.decode(g, "og:", "video:")
+		}
+		return b, &b.data.WebsiteData
+	case "video.episode":
+		b := Episode()
+		for _, g := range groupProperties(props, append(roots, "video:actor", "video:director", "video:writer", "video:series")...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "video.other":
+		b := VideoOther()
+		for _, g := range groupProperties(props, append(roots, "video:actor", "video:director", "video:writer")...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "product", "product.item":
+		b := Product()
+		for _, g := range groupProperties(props, roots...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "product.group":
+		b := ProductGroup()
+		for _, g := range groupProperties(props, roots...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "place":
+		b := Place()
+		for _, g := range groupProperties(props, roots...) {
+			b.data.decode(g)
+		}
+		return b, &b.data.WebsiteData
+	case "business.business":
+		b := Business()
+		validDay := false
+		for _, g := range groupProperties(props, roots...) {
+			b.data.decode(g, &validDay)
+		}
+		return b, &b.data.WebsiteData
+	}
+	b := Website()
+	for _, g := range groupProperties(props, roots...) {
+		b.data.decode(g)
+	}
+	return b, &b.data
+}
+
+func parseInt(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+func parseDuration(s string) *time.Duration {
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil
+	}
+	d := time.Duration(seconds) * time.Second
+	return &d
+}
+
+func parseTime(s string) (time.Time, bool) {
+	t, err := ParseDateTime(s)
+	return t.Time(), err == nil
+}
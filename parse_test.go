@@ -0,0 +1,358 @@
+package ogp_test
+
+import (
+	"net/url"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	date := time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)
+	profile := func(name string) *ogp.ProfileBuilder {
+		return ogp.Profile().
+			URL("http://example.com/profile/" + name).
+			FirstName(name).
+			Image(ogp.Image().URL("http://example.com/" + name + ".jpg").Width(64).Height(64))
+	}
+	image := ogp.Image().
+		URL("http://example.com/image.jpg").
+		SecureURL("https://example.com/image.jpg").
+		MIME("image/jpeg").
+		Alt("An image").
+		Width(400).
+		Height(300)
+	tests := []ogp.Object{
+		ogp.Website().Title("Example").URL("http://example.com").Description("Example website").
+			Determiner("the").Locale("en_US").Locale("fr_FR").SiteName("Example").Image(image).
+			Video(ogp.Video().URL("http://example.com/video.mp4").MIME("video/mp4").Width(640).Height(480)).
+			Audio(ogp.Audio().URL("http://example.com/audio.mp3").MIME("audio/mpeg")).
+			SeeAlso("http://example.com/about").Facebook(ogp.Facebook().AppID("1234").Admin("42").Page("7")),
+		ogp.Article().Title("Article").URL("http://example.com/article").Image(image).
+			PublishedTime(date).ModifiedTime(date).ExpirationTime(date).Section("News").
+			Tag("a").Tag("b").Author(profile("alice")).Author(profile("bob")).
+			Publisher("https://www.facebook.com/example").Facebook(ogp.Facebook().AppID("1234")),
+		ogp.Book().Title("Book").URL("http://example.com/book").ISBN("9780174325482").
+			ReleaseDate(ogp.NewDate(1859, time.November, 26)).Tag("novel").Author(profile("charles")),
+		ogp.Profile().Title("Profile").URL("http://example.com/profile").Image(image).
+			FirstName("John").LastName("Smith").Username("jsmith").Gender("male"),
+		ogp.Song().Title("Song").URL("http://example.com/song").Duration(3*time.Minute).
+			Album("http://example.com/album", 1, 3).Album("http://example.com/best-of", 0, 7).
+			Musician(profile("singer")),
+		ogp.Album().Title("Album").URL("http://example.com/album").ReleaseDate(ogp.TimeOf(date)).
+			Song("http://example.com/song/1", 1, 1).Song("http://example.com/song/2", 1, 2).
+			Musician(profile("singer")),
+		ogp.Playlist().Title("Playlist").URL("http://example.com/playlist").
+			Song("http://example.com/song/1", 0, 1).Creator(profile("dj")).
+			Track(ogp.Song().Title("Song").URL("http://example.com/song/2").Duration(3*time.Minute).Musician(profile("singer")), 0, 2),
+		ogp.RadioStation().Title("Radio").URL("http://example.com/radio").Creator(profile("dj")),
+		ogp.Movie().Title("Movie").URL("http://example.com/movie").Duration(2*time.Hour).ReleaseDate(ogp.TimeOf(date)).
+			Tag("drama").Actor(profile("actor"), "Hero").Actor(profile("extra"), "").
+			Director(profile("director")).Writer(profile("writer")),
+		ogp.TVShow().Title("Show").URL("http://example.com/show").Duration(30 * time.Minute).Tag("comedy"),
+		ogp.Episode().Title("Episode").URL("http://example.com/show/1").Duration(30*time.Minute).
+			Actor(profile("actor"), "Sidekick").
+			Series(ogp.TVShow().Title("Show").URL("http://example.com/show")),
+		ogp.VideoOther().Title("Clip").URL("http://example.com/clip").Duration(1 * time.Minute).
+			Writer(profile("writer")),
+		ogp.Product().Title("Shoes").URL("http://example.com/shoes").Image(image).
+			Price(ogp.MustParseDecimal("49.90"), "EUR").SalePrice(ogp.MustParseDecimal("39.00"), "EUR").
+			SaleDates(date, date.AddDate(0, 0, 7)).Availability(ogp.AvailabilityPreorder).
+			Condition(ogp.ConditionUsed).RetailerItemID("SH-42").Brand("Acme").ItemGroupID("SH"),
+		ogp.ProductGroup().Title("Shoes").URL("http://example.com/shoes").RetailerGroupID("SH").Brand("Acme"),
+		ogp.Place().Title("Tower").URL("http://example.com/tower").Location(48.8584, 2.2945).Altitude(35),
+		ogp.Website().Title("Bar").URL("http://example.com/bar").UpdatedTime(date).TTL(time.Hour).
+			RichAttachment(true).Restrictions(ogp.Restrictions().DisallowCountry("FR").Age(ogp.RestrictionAge18)),
+		ogp.Business().Title("Bakery").URL("http://example.com/bakery").
+			Contact(ogp.Contact().StreetAddress("1 Main St").Locality("Springfield").PostalCode("12345").
+				CountryName("USA").PhoneNumber("+1 555 0100")).
+			Hours(time.Monday, ogp.TimeOfDay{Hour: 7}, ogp.TimeOfDay{Hour: 19, Minute: 30}).
+			Hours(time.Saturday, ogp.TimeOfDay{Hour: 8}, ogp.TimeOfDay{Hour: 12}).
+			Location(39.78, -89.65),
+	}
+	for _, test := range tests {
+		expected := string(test.HTML())
+		document := "<!DOCTYPE html>\n<html>\n<head>\n" + expected + "\n</head>\n<body></body>\n</html>"
+		object, err := ogp.Parse(strings.NewReader(document))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if result := string(object.HTML()); result != expected {
+			t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+		}
+		if object.Type() != test.Type() {
+			t.Errorf("unexpected type: %s, expected: %s", object.Type(), test.Type())
+		}
+		if !reflect.DeepEqual(object.Properties(), test.Properties()) {
+			t.Errorf("unexpected properties: %v", object.Properties())
+		}
+	}
+}
+
+func TestParseStructuredProperties(t *testing.T) {
+	document := `<html><head>
+		<title>Ignored <meta property="og:title" content="not a tag"></title>
+		<!-- <meta property="og:title" content="commented out"> -->
+		<script>document.write('<meta property="og:title" content="scripted">')</script>
+		<META PROPERTY="og:type" CONTENT="music.album">
+		<meta property=og:title content='Greatest &amp; Latest' />
+		<meta name="og:url" content="http://example.com/album">
+		<meta property="og:image:url" content="http://example.com/front.jpg">
+		<meta property="og:image:width" content="600">
+		<meta property="og:image" content="http://example.com/back.jpg">
+		<meta property="og:image:height" content="300">
+		<meta property="music:song" content="http://example.com/song/1">
+		<meta property="music:song:disc" content="1">
+		<meta property="music:song:track" content="1">
+		<meta property="music:song" content="http://example.com/song/2">
+		<meta property="music:song:track" content="2">
+		<meta property="twitter:card" content="summary">
+	</head></html>`
+	object, err := ogp.Parse(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	album, ok := object.(*ogp.MusicAlbumBuilder)
+	if !ok {
+		t.Fatalf("unexpected object type: %T", object)
+	}
+	songs := []ogp.MusicSongRef{
+		{URL: "http://example.com/song/1", Disc: 1, Track: 1},
+		{URL: "http://example.com/song/2", Track: 2},
+	}
+	if !reflect.DeepEqual(album.Data().Songs, songs) {
+		t.Errorf("unexpected songs: %+v", album.Data().Songs)
+	}
+	expected := `<meta property="og:type" content="music.album">
+<meta property="og:title" content="Greatest &amp; Latest">
+<meta property="og:url" content="http://example.com/album">
+<meta property="og:image" content="http://example.com/front.jpg">
+<meta property="og:image:width" content="600">
+<meta property="og:image" content="http://example.com/back.jpg">
+<meta property="og:image:height" content="300">
+<meta property="music:song" content="http://example.com/song/1">
+<meta property="music:song:disc" content="1">
+<meta property="music:song:track" content="1">
+<meta property="music:song" content="http://example.com/song/2">
+<meta property="music:song:track" content="2">`
+	if result := string(object.HTML()); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestParseNoProperties(t *testing.T) {
+	_, err := ogp.Parse(strings.NewReader(`<html><head><title>Nothing</title></head></html>`))
+	if err != ogp.ErrNoProperties {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParseManyComments(t *testing.T) {
+	// Skipping comments doesn't grow the stack.
+	defer debug.SetMaxStack(debug.SetMaxStack(1 << 20))
+	document := "<html><head>" + strings.Repeat("<!----><!x><?x></>", 1<<16) +
+		`<meta property="og:title" content="Example"></head></html>`
+	object, err := ogp.Parse(strings.NewReader(document))
+	if err != nil || title(t, object) != "Example" {
+		t.Errorf("unexpected result: %v, %v", object, err)
+	}
+}
+
+func TestParseCustomProperties(t *testing.T) {
+	document := `<html prefix="og: https://ogp.me/ns# product: https://ogp.me/ns/product#"><head>
+		<meta property="og:type" content="website">
+		<meta property="og:title" content="Shop">
+		<meta property="og:updated_time" content="2020-05-01T10:30:00Z">
+		<meta property="product:price:amount" content="9.99">
+		<meta property="product:price:currency" content="EUR">
+		<meta property="unknown:url" content="example://shop">
+	</head></html>`
+	object, err := ogp.Parse(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `<meta property="og:type" content="website">
+<meta property="og:title" content="Shop">
+<meta property="og:updated_time" content="2020-05-01T10:30:00Z">
+<meta property="product:price:amount" content="9.99">
+<meta property="product:price:currency" content="EUR">`
+	if result := object.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	prefix := "og: https://ogp.me/ns# website: https://ogp.me/ns/website# product: https://ogp.me/ns/product#"
+	if result := ogp.Prefix(object); result != prefix {
+		t.Errorf("unexpected prefix: %s", result)
+	}
+	document = string(ogp.Head(object)) + "\n" + object.String() + "\n</head>"
+	if object, err = ogp.Parse(strings.NewReader(document)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := object.String(); result != expected {
+		t.Errorf("unexpected result after round trip:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	document := `<html lang="en-us"><head>
+		<title>
+			Example   page
+		</title>
+		<meta name="description" content="A page without Open Graph properties.">
+		<link rel="alternate canonical" href="http://example.com/page">
+	</head><body>
+		<img src="http://example.com/pixel.gif" width="1" height="1">
+		<img src="http://example.com/unsized.jpg">
+		<img src="http://example.com/photo.jpg" width="800" height="600" alt="A photo">
+		<img src="http://example.com/thumbnail.jpg" width="200" height="150">
+	</body></html>`
+	p := &ogp.Parser{Fallback: true}
+	object, err := p.Parse(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `<meta property="og:type" content="website">
+<meta property="og:title" content="Example page">
+<meta property="og:url" content="http://example.com/page">
+<meta property="og:description" content="A page without Open Graph properties.">
+<meta property="og:locale" content="en_US">
+<meta property="og:image" content="http://example.com/photo.jpg">
+<meta property="og:image:alt" content="A photo">
+<meta property="og:image:width" content="800">
+<meta property="og:image:height" content="600">`
+	if result := object.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	sources := map[string]ogp.Source{
+		"og:title":       ogp.SourceTitle,
+		"og:description": ogp.SourceDescription,
+		"og:url":         ogp.SourceCanonical,
+		"og:locale":      ogp.SourceLang,
+		"og:image":       ogp.SourceImage,
+	}
+	if result := object.(*ogp.WebsiteBuilder).Data().Sources; !reflect.DeepEqual(result, sources) {
+		t.Errorf("unexpected sources: %v", result)
+	}
+	if _, err := p.Parse(strings.NewReader(`<html><head></head></html>`)); err != ogp.ErrNoProperties {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParseFallbackSources(t *testing.T) {
+	document := `<html><head>
+		<title>Ignored</title>
+		<meta property="og:title" content="Real title">
+		<meta name="twitter:description" content="From the card">
+		<meta name="twitter:image" content="http://example.com/card.jpg">
+		<meta name="twitter:image:alt" content="A card">
+		<meta name="description" content="Ignored">
+		<script type="application/ld+json">{
+			"@context": "https://schema.org",
+			"@graph": [
+				{"@type": "Organization", "name": "Ignored"},
+				{"@type": "WebPage", "name": "Ignored", "url": "http://example.com/ignored"},
+				{"@type": ["NewsArticle"], "headline": "Ignored", "url": "http://example.com/news",
+					"datePublished": "2020-05-01", "dateModified": "not a date", "inLanguage": "fr-FR",
+					"publisher": {"@type": "Organization", "name": "Example News"},
+					"author": [{"@type": "Person", "name": "Alice", "url": "http://example.com/alice"}, "Bob"]}
+			]
+		}</script>
+	</head><body><img src="http://example.com/photo.jpg" width="800" height="600"></body></html>`
+	object, err := (&ogp.Parser{Fallback: true}).Parse(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	article, ok := object.(*ogp.ArticleBuilder)
+	if !ok {
+		t.Fatalf("unexpected object type: %T", object)
+	}
+	data := article.Data()
+	if data.Title != "Real title" || data.Description != "From the card" || data.URL != "http://example.com/news" ||
+		data.SiteName != "Example News" || !reflect.DeepEqual(data.Locales, []string{"fr_FR"}) {
+		t.Errorf("unexpected data: %+v", data.WebsiteData)
+	}
+	if images := []ogp.ImageData{{URL: "http://example.com/card.jpg", Alt: "A card"}}; !reflect.DeepEqual(data.Images, images) {
+		t.Errorf("unexpected images: %+v", data.Images)
+	}
+	if data.PublishedTime == nil || !data.PublishedTime.Equal(time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)) || data.ModifiedTime != nil {
+		t.Errorf("unexpected times: %v, %v", data.PublishedTime, data.ModifiedTime)
+	}
+	if len(data.Authors) != 1 || data.Authors[0].URL != "http://example.com/alice" || data.Authors[0].Title != "Alice" {
+		t.Errorf("unexpected authors: %+v", data.Authors)
+	}
+	sources := map[string]ogp.Source{
+		"og:type":                ogp.SourceJSONLD,
+		"og:description":         ogp.SourceTwitter,
+		"og:url":                 ogp.SourceJSONLD,
+		"og:locale":              ogp.SourceJSONLD,
+		"og:site_name":           ogp.SourceJSONLD,
+		"og:image":               ogp.SourceTwitter,
+		"article:published_time": ogp.SourceJSONLD,
+		"article:author":         ogp.SourceJSONLD,
+	}
+	if !reflect.DeepEqual(data.Sources, sources) {
+		t.Errorf("unexpected sources: %v", data.Sources)
+	}
+}
+
+func TestParseFallbackImageURL(t *testing.T) {
+	for _, name := range []string{"og:image", "og:image:url", "og:image:secure_url"} {
+		document := `<html><head>
+			<meta property="` + name + `" content="https://example.com/real.jpg">
+		</head><body><img src="https://example.com/photo.jpg" width="800" height="600"></body></html>`
+		object, err := (&ogp.Parser{Fallback: true}).Parse(strings.NewReader(document))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		data := object.(*ogp.WebsiteBuilder).Data()
+		if len(data.Images) != 1 || data.Sources["og:image"] != "" {
+			t.Errorf("%s: unexpected images: %+v", name, data.Images)
+		}
+	}
+}
+
+func TestParseBase(t *testing.T) {
+	document := `<html><head>
+		<base href="/docs/">
+		<meta property="og:type" content="article">
+		<meta property="og:url" content="HTTPS://Example.com:443/docs/page">
+		<meta property="og:image" content="img/a.png">
+		<meta property="og:image:secure_url" content="/img/a.png">
+		<meta property="article:author" content="../people/alice">
+		<meta property="article:author:image" content="alice.png">
+		<meta property="article:section" content="news">
+	</head></html>`
+	base, _ := url.Parse("https://example.com/page")
+	object, err := (&ogp.Parser{Base: base}).Parse(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `<meta property="og:type" content="article">
+<meta property="og:url" content="https://example.com/docs/page">
+<meta property="og:image" content="https://example.com/docs/img/a.png">
+<meta property="og:image:secure_url" content="https://example.com/img/a.png">
+<meta property="article:section" content="news">
+<meta property="article:author" content="https://example.com/people/alice">
+<meta property="article:author:image" content="https://example.com/docs/alice.png">`
+	if result := object.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	document = strings.Replace(document, `href="/docs/"`, `href="http://cdn.example.com/"`, 1)
+	if object, err = ogp.Parse(strings.NewReader(document)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := object.(*ogp.ArticleBuilder).Data().Images[0].URL; result != "http://cdn.example.com/img/a.png" {
+		t.Errorf("unexpected image: %s", result)
+	}
+	document = strings.Replace(document, `<base href="http://cdn.example.com/">`, "", 1)
+	if object, err = ogp.Parse(strings.NewReader(document)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := object.(*ogp.ArticleBuilder).Data().Images[0].URL; result != "img/a.png" {
+		t.Errorf("unexpected image: %s", result)
+	}
+}
@@ -0,0 +1,323 @@
+package ogp
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+// PlaceData holds the properties of a `place` object.
+type PlaceData struct {
+	WebsiteData
+	Location *LocationData `json:"location,omitempty"`
+}
+
+// LocationData holds the `place:location:*` properties, the WGS 84
+// coordinates of a place.
+type LocationData struct {
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Altitude  *float64 `json:"altitude,omitempty"`
+}
+
+// PlaceBuilder builds a `place` object.
+type PlaceBuilder struct {
+	data PlaceData
+}
+
+// Title sets the `place:title` property.
+func (b *PlaceBuilder) Title(title string) *PlaceBuilder {
+	b.data.Title = title
+	return b
+}
+
+// URL sets the `place:url` property.
+func (b *PlaceBuilder) URL(url string) *PlaceBuilder {
+	b.data.URL = url
+	return b
+}
+
+// Description sets the `place:description` property.
+func (b *PlaceBuilder) Description(description string) *PlaceBuilder {
+	b.data.Description = description
+	return b
+}
+
+// Determiner sets the `place:determiner` property.
+func (b *PlaceBuilder) Determiner(determiner string) *PlaceBuilder {
+	b.data.Determiner = determiner
+	return b
+}
+
+// Locale sets the `place:locale` or adds a new `place:locale:alternate` property.
+func (b *PlaceBuilder) Locale(locale string) *PlaceBuilder {
+	b.data.Locales = append(b.data.Locales, locale)
+	return b
+}
+
+// SiteName sets the `place:site_name` property.
+func (b *PlaceBuilder) SiteName(siteName string) *PlaceBuilder {
+	b.data.SiteName = siteName
+	return b
+}
+
+// Image adds a new `place:image` property.
+func (b *PlaceBuilder) Image(image *ImageBuilder) *PlaceBuilder {
+	b.data.Images = append(b.data.Images, image.data)
+	return b
+}
+
+// Video adds a new `place:video` property.
+func (b *PlaceBuilder) Video(video *VideoBuilder) *PlaceBuilder {
+	b.data.Videos = append(b.data.Videos, video.data)
+	return b
+}
+
+// Audio adds a new `place:audio` property.
+func (b *PlaceBuilder) Audio(audio *AudioBuilder) *PlaceBuilder {
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *PlaceBuilder) Facebook(facebook *FacebookBuilder) *PlaceBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *PlaceBuilder) AppLink(appLink *AppLinkBuilder) *PlaceBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *PlaceBuilder) Restrictions(restrictions *RestrictionsBuilder) *PlaceBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *PlaceBuilder) TTL(ttl time.Duration) *PlaceBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *PlaceBuilder) UpdatedTime(updatedTime time.Time) *PlaceBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *PlaceBuilder) RichAttachment(richAttachment bool) *PlaceBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *PlaceBuilder) Property(ns, prop, content string) *PlaceBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *PlaceBuilder) Namespace(prefix, uri string) *PlaceBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *PlaceBuilder) Defaults(defaults *Defaults) *PlaceBuilder {
+	b.data.defaults = defaults
+	return b
+}
+
+// Location sets the `place:location:latitude` and `place:location:longitude`
+// properties, in decimal degrees.
+func (b *PlaceBuilder) Location(latitude, longitude float64) *PlaceBuilder {
+	b.data.location().Latitude = latitude
+	b.data.location().Longitude = longitude
+	return b
+}
+
+// Altitude sets the `place:location:altitude` property, in meters.
+func (b *PlaceBuilder) Altitude(altitude float64) *PlaceBuilder {
+	b.data.location().Altitude = &altitude
+	return b
+}
+
+// Data returns the properties of the `place` object. Changes made
+// to the returned value are reflected in the builder.
+func (b *PlaceBuilder) Data() *PlaceData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `place` object.
+func (d PlaceData) Builder() *PlaceBuilder {
+	return &PlaceBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `place`.
+func (b *PlaceBuilder) Type() string {
+	return "place"
+}
+
+// Validate checks the `place` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *PlaceBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
+// HTML renders the `place` object to be used in HTML templates.
+func (b *PlaceBuilder) HTML() template.HTML {
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `place` object as a schema.org `Place` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *PlaceBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `place` object as HTML markup.
+func (b *PlaceBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `place` object, in rendering
+// order.
+func (b *PlaceBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `place` object.
+func (b *PlaceBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `place` object as HTML markup into w.
+func (b *PlaceBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
+}
+
+func (b *PlaceBuilder) meta() *metaBuilder {
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *PlaceData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "place")
+	d.Location.meta(mb)
+}
+
+func (d *PlaceData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	if d.Location == nil {
+		v.add("", "place:location:latitude", RuleRequired)
+		v.add("", "place:location:longitude", RuleRequired)
+	}
+	d.Location.validate(v, "")
+}
+
+func (d *PlaceData) linkedData() linkedData {
+	ld := d.baseLinkedData("Place")
+	d.Location.setLinkedData(ld)
+	return ld
+}
+
+func (d *PlaceData) decode(g *group) {
+	if !decodeLocation(&d.Location, g) {
+		d.baseDecode(g, "og:")
+	}
+}
+
+func (d *PlaceData) location() *LocationData {
+	if d.Location == nil {
+		d.Location = &LocationData{}
+	}
+	return d.Location
+}
+
+func (d *LocationData) meta(mb *metaBuilder) {
+	if d == nil {
+		return
+	}
+	mb.Add("place", "location:latitude", formatFloat(d.Latitude))
+	mb.Add("place", "location:longitude", formatFloat(d.Longitude))
+	if d.Altitude != nil {
+		mb.Add("place", "location:altitude", formatFloat(*d.Altitude))
+	}
+}
+
+func (d *LocationData) validate(v *validator, path string) {
+	if d == nil {
+		return
+	}
+	v.inRange(path, "place:location:latitude", d.Latitude, -90, 90)
+	v.inRange(path, "place:location:longitude", d.Longitude, -180, 180)
+}
+
+func (d *LocationData) setLinkedData(ld linkedData) {
+	if d == nil {
+		return
+	}
+	geo := linkedData{"@type": "GeoCoordinates", "latitude": d.Latitude, "longitude": d.Longitude}
+	if d.Altitude != nil {
+		geo["elevation"] = *d.Altitude
+	}
+	ld.set("geo", geo)
+}
+
+// decodeLocation decodes a `place:location:*` property into the location,
+// created if needed, and reports whether g is one.
+func decodeLocation(location **LocationData, g *group) bool {
+	value, err := strconv.ParseFloat(g.Content, 64)
+	if err != nil {
+		return false
+	}
+	d := *location
+	if d == nil {
+		d = &LocationData{}
+	}
+	switch g.Name {
+	case "place:location:latitude":
+		d.Latitude = value
+	case "place:location:longitude":
+		d.Longitude = value
+	case "place:location:altitude":
+		d.Altitude = &value
+	default:
+		return false
+	}
+	*location = d
+	return true
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,185 @@
+package ogp_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestPlace(t *testing.T) {
+	place := ogp.Place().
+		Title("Eiffel Tower").
+		URL("http://example.com/eiffel-tower").
+		Image(ogp.Image().URL("http://example.com/eiffel-tower.jpg")).
+		Location(48.8584, 2.2945).
+		Altitude(35)
+	expected := `<meta property="og:type" content="place">
+<meta property="og:title" content="Eiffel Tower">
+<meta property="og:url" content="http://example.com/eiffel-tower">
+<meta property="og:image" content="http://example.com/eiffel-tower.jpg">
+<meta property="place:location:latitude" content="48.8584">
+<meta property="place:location:longitude" content="2.2945">
+<meta property="place:location:altitude" content="35">`
+	if result := place.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if err := place.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if prefix := ogp.Prefix(place); prefix != "og: https://ogp.me/ns# place: https://ogp.me/ns/place#" {
+		t.Errorf("unexpected prefix: %s", prefix)
+	}
+	if result := string(place.JSONLD()); !strings.Contains(result, `"geo":{"@type":"GeoCoordinates","elevation":35,"latitude":48.8584,"longitude":2.2945}`) {
+		t.Errorf("unexpected JSON-LD: %s", result)
+	}
+}
+
+func TestPlaceValidate(t *testing.T) {
+	tests := []struct {
+		place    *ogp.PlaceBuilder
+		expected string
+	}{
+		{
+			place:    ogp.Place(),
+			expected: "ogp: place:location:latitude is required\nogp: place:location:longitude is required",
+		},
+		{
+			place:    ogp.Place().Location(90.5, -180.1),
+			expected: "ogp: place:location:latitude is out of range\nogp: place:location:longitude is out of range",
+		},
+	}
+	for _, test := range tests {
+		test.place.Title("Tower").URL("http://example.com/tower").Image(ogp.Image().URL("http://example.com/tower.jpg"))
+		if err := test.place.Validate(); err == nil || err.Error() != test.expected {
+			t.Errorf("unexpected error: %v, expected: %s", err, test.expected)
+		}
+	}
+}
+
+func TestBusiness(t *testing.T) {
+	business := ogp.Business().
+		Title("Corner Bakery").
+		URL("http://example.com/bakery").
+		Image(ogp.Image().URL("http://example.com/bakery.jpg")).
+		Contact(ogp.Contact().
+			StreetAddress("1 Main St").
+			Locality("Springfield").
+			Region("IL").
+			PostalCode("62701").
+			CountryName("USA").
+			PhoneNumber("+1 555 0100").
+			Website("http://example.com")).
+		Hours(time.Monday, ogp.TimeOfDay{Hour: 7}, ogp.TimeOfDay{Hour: 19, Minute: 30}).
+		Hours(time.Saturday, ogp.TimeOfDay{Hour: 22}, ogp.TimeOfDay{Hour: 2}).
+		Location(39.7817, -89.6501)
+	expected := `<meta property="og:type" content="business.business">
+<meta property="og:title" content="Corner Bakery">
+<meta property="og:url" content="http://example.com/bakery">
+<meta property="og:image" content="http://example.com/bakery.jpg">
+<meta property="business:contact_data:street_address" content="1 Main St">
+<meta property="business:contact_data:locality" content="Springfield">
+<meta property="business:contact_data:region" content="IL">
+<meta property="business:contact_data:postal_code" content="62701">
+<meta property="business:contact_data:country_name" content="USA">
+<meta property="business:contact_data:phone_number" content="+1 555 0100">
+<meta property="business:contact_data:website" content="http://example.com">
+<meta property="business:hours:day" content="monday">
+<meta property="business:hours:start" content="07:00">
+<meta property="business:hours:end" content="19:30">
+<meta property="business:hours:day" content="saturday">
+<meta property="business:hours:start" content="22:00">
+<meta property="business:hours:end" content="02:00">
+<meta property="place:location:latitude" content="39.7817">
+<meta property="place:location:longitude" content="-89.6501">`
+	if result := business.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if err := business.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if prefix := ogp.Prefix(business); prefix != "og: https://ogp.me/ns# business: https://ogp.me/ns/business# place: https://ogp.me/ns/place#" {
+		t.Errorf("unexpected prefix: %s", prefix)
+	}
+	if result := string(business.JSONLD()); !strings.Contains(result, `"dayOfWeek":"https://schema.org/Monday"`) {
+		t.Errorf("unexpected JSON-LD: %s", result)
+	}
+}
+
+func TestParseBusinessHours(t *testing.T) {
+	document := `<html><head>
+		<meta property="og:type" content="business.business">
+		<meta property="og:title" content="Bakery">
+		<meta property="business:hours:start" content="06:00">
+		<meta property="business:hours:day" content="monday">
+		<meta property="business:hours:start" content="07:00">
+		<meta property="business:hours:end" content="19:30">
+		<meta property="business:hours:day" content="someday">
+		<meta property="business:hours:start" content="01:00">
+		<meta property="business:hours:end" content="02:00">
+		<meta property="business:hours:day" content="saturday">
+		<meta property="business:hours:start" content="22:00">
+		<meta property="business:hours:end" content="23:00">
+	</head></html>`
+	object, err := ogp.Parse(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []ogp.BusinessHoursData{
+		{Day: time.Monday, Start: ogp.TimeOfDay{Hour: 7}, End: ogp.TimeOfDay{Hour: 19, Minute: 30}},
+		{Day: time.Saturday, Start: ogp.TimeOfDay{Hour: 22}, End: ogp.TimeOfDay{Hour: 23}},
+	}
+	if result := object.(*ogp.BusinessBuilder).Data().Hours; !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected hours: %+v", result)
+	}
+}
+
+func TestBusinessValidate(t *testing.T) {
+	business := ogp.Business().
+		Title("Bakery").
+		URL("http://example.com/bakery").
+		Image(ogp.Image().URL("http://example.com/bakery.jpg")).
+		Contact(ogp.Contact().Locality("Springfield").Website("example.com")).
+		Hours(time.Weekday(7), ogp.TimeOfDay{Hour: 24, Minute: 30}, ogp.TimeOfDay{Hour: 9, Minute: 60}).
+		Location(-91, 0)
+	expected := `ogp: business:contact_data:street_address is required
+ogp: business:contact_data:postal_code is required
+ogp: business:contact_data:country_name is required
+ogp: business:contact_data:website must be an absolute URL
+ogp: business:hours[0]: business:hours:day has an unknown value
+ogp: business:hours[0]: business:hours:start is out of range
+ogp: business:hours[0]: business:hours:end is out of range
+ogp: place:location:latitude is out of range`
+	if err := business.Validate(); err == nil || err.Error() != expected {
+		t.Errorf("unexpected error: %v", err)
+	}
+	business = ogp.Business().Title("Bakery").URL("http://example.com/bakery")
+	if err := business.Validate(); err == nil || business.Data().Contact != nil {
+		t.Errorf("unexpected result: %v, %v", err, business.Data().Contact)
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected ogp.TimeOfDay
+		valid    bool
+	}{
+		{"07:00", ogp.TimeOfDay{Hour: 7}, true},
+		{"9:30", ogp.TimeOfDay{Hour: 9, Minute: 30}, true},
+		{"24:00", ogp.TimeOfDay{Hour: 24}, true},
+		{"24:01", ogp.TimeOfDay{}, false},
+		{"12:60", ogp.TimeOfDay{}, false},
+		{"1230", ogp.TimeOfDay{}, false},
+		{"12:3", ogp.TimeOfDay{}, false},
+		{"+1:00", ogp.TimeOfDay{}, false},
+	}
+	for _, test := range tests {
+		result, err := ogp.ParseTimeOfDay(test.text)
+		if (err == nil) != test.valid || result != test.expected {
+			t.Errorf("%s: unexpected result: %v, %v", test.text, result, err)
+		}
+	}
+}
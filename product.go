@@ -0,0 +1,433 @@
+package ogp
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// Values of the `product:availability` property.
+const (
+	AvailabilityInStock           = "in stock"
+	AvailabilityOutOfStock        = "out of stock"
+	AvailabilityPreorder          = "preorder"
+	AvailabilityAvailableForOrder = "available for order"
+	AvailabilityDiscontinued      = "discontinued"
+	AvailabilityPending           = "pending"
+)
+
+// Values of the `product:condition` property.
+const (
+	ConditionNew         = "new"
+	ConditionRefurbished = "refurbished"
+	ConditionUsed        = "used"
+)
+
+// PriceData holds the amount and the ISO 4217 currency of a price.
+type PriceData struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+// ProductData holds the properties of a `product.item` object.
+type ProductData struct {
+	WebsiteData
+	Price          *PriceData `json:"price,omitempty"`
+	SalePrice      *PriceData `json:"sale_price,omitempty"`
+	SaleStart      *time.Time `json:"sale_start,omitempty"`
+	SaleEnd        *time.Time `json:"sale_end,omitempty"`
+	Availability   string     `json:"availability,omitempty"`
+	Condition      string     `json:"condition,omitempty"`
+	RetailerItemID string     `json:"retailer_item_id,omitempty"`
+	Brand          string     `json:"brand,omitempty"`
+	ItemGroupID    string     `json:"item_group_id,omitempty"`
+}
+
+// ProductBuilder builds a `product.item` object.
+type ProductBuilder struct {
+	data ProductData
+}
+
+// Title sets the `product:title` property.
+func (b *ProductBuilder) Title(title string) *ProductBuilder {
+	b.data.Title = title
+	return b
+}
+
+// URL sets the `product:url` property.
+func (b *ProductBuilder) URL(url string) *ProductBuilder {
+	b.data.URL = url
+	return b
+}
+
+// Description sets the `product:description` property.
+func (b *ProductBuilder) Description(description string) *ProductBuilder {
+	b.data.Description = description
+	return b
+}
+
+// Determiner sets the `product:determiner` property.
+func (b *ProductBuilder) Determiner(determiner string) *ProductBuilder {
+	b.data.Determiner = determiner
+	return b
+}
+
+// Locale sets the `product:locale` or adds a new `product:locale:alternate` property.
+func (b *ProductBuilder) Locale(locale string) *ProductBuilder {
+	b.data.Locales = append(b.data.Locales, locale)
+	return b
+}
+
+// SiteName sets the `product:site_name` property.
+func (b *ProductBuilder) SiteName(siteName string) *ProductBuilder {
+	b.data.SiteName = siteName
+	return b
+}
+
+// Image adds a new `product:image` property.
+func (b *ProductBuilder) Image(image *ImageBuilder) *ProductBuilder {
+	b.data.Images = append(b.data.Images, image.data)
+	return b
+}
+
+// Video adds a new `product:video` property.
+func (b *ProductBuilder) Video(video *VideoBuilder) *ProductBuilder {
+	b.data.Videos = append(b.data.Videos, video.data)
+	return b
+}
+
+// Audio adds a new `product:audio` property.
+func (b *ProductBuilder) Audio(audio *AudioBuilder) *ProductBuilder {
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *ProductBuilder) Facebook(facebook *FacebookBuilder) *ProductBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *ProductBuilder) AppLink(appLink *AppLinkBuilder) *ProductBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *ProductBuilder) Restrictions(restrictions *RestrictionsBuilder) *ProductBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *ProductBuilder) TTL(ttl time.Duration) *ProductBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *ProductBuilder) UpdatedTime(updatedTime time.Time) *ProductBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *ProductBuilder) RichAttachment(richAttachment bool) *ProductBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *ProductBuilder) Property(ns, prop, content string) *ProductBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *ProductBuilder) Namespace(prefix, uri string) *ProductBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *ProductBuilder) Defaults(defaults *Defaults) *ProductBuilder {
+	b.data.defaults = defaults
+	return b
+}
+
+// Price sets the `product:price:amount` and `product:price:currency`
+// properties.
+func (b *ProductBuilder) Price(amount Decimal, currency string) *ProductBuilder {
+	b.data.Price = &PriceData{Amount: amount, Currency: currency}
+	return b
+}
+
+// SalePrice sets the `product:sale_price:amount` and
+// `product:sale_price:currency` properties.
+func (b *ProductBuilder) SalePrice(amount Decimal, currency string) *ProductBuilder {
+	b.data.SalePrice = &PriceData{Amount: amount, Currency: currency}
+	return b
+}
+
+// SaleDates sets the `product:sale_price_dates:start` and
+// `product:sale_price_dates:end` properties.
+func (b *ProductBuilder) SaleDates(start, end time.Time) *ProductBuilder {
+	b.data.SaleStart = &start
+	b.data.SaleEnd = &end
+	return b
+}
+
+// Availability sets the `product:availability` property, e.g.
+// AvailabilityInStock.
+func (b *ProductBuilder) Availability(availability string) *ProductBuilder {
+	b.data.Availability = availability
+	return b
+}
+
+// Condition sets the `product:condition` property, e.g. ConditionNew.
+func (b *ProductBuilder) Condition(condition string) *ProductBuilder {
+	b.data.Condition = condition
+	return b
+}
+
+// RetailerItemID sets the `product:retailer_item_id` property.
+func (b *ProductBuilder) RetailerItemID(id string) *ProductBuilder {
+	b.data.RetailerItemID = id
+	return b
+}
+
+// Brand sets the `product:brand` property.
+func (b *ProductBuilder) Brand(brand string) *ProductBuilder {
+	b.data.Brand = brand
+	return b
+}
+
+// ItemGroupID sets the `product:item_group_id` property, the retailer ID of
+// the group of variants the product belongs to.
+func (b *ProductBuilder) ItemGroupID(id string) *ProductBuilder {
+	b.data.ItemGroupID = id
+	return b
+}
+
+// Data returns the properties of the `product.item` object. Changes made
+// to the returned value are reflected in the builder.
+func (b *ProductBuilder) Data() *ProductData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `product.item` object.
+func (d ProductData) Builder() *ProductBuilder {
+	return &ProductBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `product.item`.
+func (b *ProductBuilder) Type() string {
+	return "product.item"
+}
+
+// Validate checks the `product.item` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *ProductBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
+// HTML renders the `product.item` object to be used in HTML templates.
+func (b *ProductBuilder) HTML() template.HTML {
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `product.item` object as a schema.org `Product` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *ProductBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `product.item` object as HTML markup.
+func (b *ProductBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `product.item` object, in rendering
+// order.
+func (b *ProductBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `product.item` object.
+func (b *ProductBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `product.item` object as HTML markup into w.
+func (b *ProductBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
+}
+
+func (b *ProductBuilder) meta() *metaBuilder {
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *ProductData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "product.item")
+	d.Price.meta(mb, "product:price")
+	if d.Availability != "" {
+		mb.Add("product", "availability", d.Availability)
+	}
+	if d.Condition != "" {
+		mb.Add("product", "condition", d.Condition)
+	}
+	if d.RetailerItemID != "" {
+		mb.Add("product", "retailer_item_id", d.RetailerItemID)
+	}
+	if d.Brand != "" {
+		mb.Add("product", "brand", d.Brand)
+	}
+	d.SalePrice.meta(mb, "product:sale_price")
+	if d.SaleStart != nil {
+		mb.Add("product", "sale_price_dates:start", d.SaleStart.Format(time.RFC3339))
+	}
+	if d.SaleEnd != nil {
+		mb.Add("product", "sale_price_dates:end", d.SaleEnd.Format(time.RFC3339))
+	}
+	if d.ItemGroupID != "" {
+		mb.Add("product", "item_group_id", d.ItemGroupID)
+	}
+}
+
+func (d *ProductData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	if d.Price == nil {
+		v.add("", "product:price:amount", RuleRequired)
+	}
+	d.Price.validate(v, "", "product:price")
+	d.SalePrice.validate(v, "", "product:sale_price")
+	v.oneOf("", "product:availability", d.Availability,
+		AvailabilityInStock, AvailabilityOutOfStock, AvailabilityPreorder,
+		AvailabilityAvailableForOrder, AvailabilityDiscontinued, AvailabilityPending)
+	v.oneOf("", "product:condition", d.Condition, ConditionNew, ConditionRefurbished, ConditionUsed)
+	if d.SaleStart != nil && d.SaleEnd != nil && d.SaleEnd.Before(*d.SaleStart) {
+		v.add("", "product:sale_price_dates:end", RuleValue)
+	}
+}
+
+// schemaAvailability maps the values of `product:availability` to the
+// schema.org item availabilities.
+var schemaAvailability = map[string]string{
+	AvailabilityInStock:           "https://schema.org/InStock",
+	AvailabilityOutOfStock:        "https://schema.org/OutOfStock",
+	AvailabilityPreorder:          "https://schema.org/PreOrder",
+	AvailabilityAvailableForOrder: "https://schema.org/BackOrder",
+	AvailabilityDiscontinued:      "https://schema.org/Discontinued",
+}
+
+// schemaCondition maps the values of `product:condition` to the schema.org
+// offer item conditions.
+var schemaCondition = map[string]string{
+	ConditionNew:         "https://schema.org/NewCondition",
+	ConditionRefurbished: "https://schema.org/RefurbishedCondition",
+	ConditionUsed:        "https://schema.org/UsedCondition",
+}
+
+func (d *ProductData) linkedData() linkedData {
+	ld := d.baseLinkedData("Product")
+	ld.set("sku", d.RetailerItemID)
+	if d.Brand != "" {
+		ld.set("brand", linkedData{"@type": "Brand", "name": d.Brand})
+	}
+	ld.set("itemCondition", schemaCondition[d.Condition])
+	ld.set("inProductGroupWithID", d.ItemGroupID)
+	if d.Price != nil {
+		offer := linkedData{"@type": "Offer", "price": d.Price.Amount.String()}
+		offer.set("priceCurrency", d.Price.Currency)
+		offer.set("availability", schemaAvailability[d.Availability])
+		ld.set("offers", offer)
+	}
+	return ld
+}
+
+func (d *ProductData) decode(g *group) {
+	switch g.Name {
+	case "product:price:amount":
+		d.price(&d.Price).Amount, _ = ParseDecimal(g.Content)
+	case "product:price:currency":
+		d.price(&d.Price).Currency = g.Content
+	case "product:sale_price:amount":
+		d.price(&d.SalePrice).Amount, _ = ParseDecimal(g.Content)
+	case "product:sale_price:currency":
+		d.price(&d.SalePrice).Currency = g.Content
+	case "product:sale_price_dates:start":
+		if t, ok := parseTime(g.Content); ok {
+			d.SaleStart = &t
+		}
+	case "product:sale_price_dates:end":
+		if t, ok := parseTime(g.Content); ok {
+			d.SaleEnd = &t
+		}
+	case "product:availability":
+		d.Availability = g.Content
+	case "product:condition":
+		d.Condition = g.Content
+	case "product:retailer_item_id":
+		d.RetailerItemID = g.Content
+	case "product:brand":
+		d.Brand = g.Content
+	case "product:item_group_id":
+		d.ItemGroupID = g.Content
+	default:
+		d.baseDecode(g, "og:")
+	}
+}
+
+func (d *ProductData) price(price **PriceData) *PriceData {
+	if *price == nil {
+		*price = &PriceData{}
+	}
+	return *price
+}
+
+func (d *PriceData) meta(mb *metaBuilder, ns string) {
+	if d == nil {
+		return
+	}
+	mb.Add(ns, "amount", d.Amount.String())
+	if d.Currency != "" {
+		mb.Add(ns, "currency", d.Currency)
+	}
+}
+
+func (d *PriceData) validate(v *validator, path, ns string) {
+	if d == nil {
+		return
+	}
+	if d.Amount.Sign() < 0 {
+		v.add(path, ns+":amount", RuleNonNegative)
+	}
+	if v.required(path, ns+":currency", d.Currency) {
+		v.currency(path, ns+":currency", d.Currency)
+	}
+}
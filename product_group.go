@@ -0,0 +1,257 @@
+package ogp
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// ProductGroupData holds the properties of a `product.group` object, the
+// variants of a product.
+type ProductGroupData struct {
+	WebsiteData
+	RetailerGroupID string `json:"retailer_group_id,omitempty"`
+	Brand           string `json:"brand,omitempty"`
+}
+
+// ProductGroupBuilder builds a `product.group` object.
+type ProductGroupBuilder struct {
+	data ProductGroupData
+}
+
+// Title sets the `product:title` property.
+func (b *ProductGroupBuilder) Title(title string) *ProductGroupBuilder {
+	b.data.Title = title
+	return b
+}
+
+// URL sets the `product:url` property.
+func (b *ProductGroupBuilder) URL(url string) *ProductGroupBuilder {
+	b.data.URL = url
+	return b
+}
+
+// Description sets the `product:description` property.
+func (b *ProductGroupBuilder) Description(description string) *ProductGroupBuilder {
+	b.data.Description = description
+	return b
+}
+
+// Determiner sets the `product:determiner` property.
+func (b *ProductGroupBuilder) Determiner(determiner string) *ProductGroupBuilder {
+	b.data.Determiner = determiner
+	return b
+}
+
+// Locale sets the `product:locale` or adds a new `product:locale:alternate` property.
+func (b *ProductGroupBuilder) Locale(locale string) *ProductGroupBuilder {
+	b.data.Locales = append(b.data.Locales, locale)
+	return b
+}
+
+// SiteName sets the `product:site_name` property.
+func (b *ProductGroupBuilder) SiteName(siteName string) *ProductGroupBuilder {
+	b.data.SiteName = siteName
+	return b
+}
+
+// Image adds a new `product:image` property.
+func (b *ProductGroupBuilder) Image(image *ImageBuilder) *ProductGroupBuilder {
+	b.data.Images = append(b.data.Images, image.data)
+	return b
+}
+
+// Video adds a new `product:video` property.
+func (b *ProductGroupBuilder) Video(video *VideoBuilder) *ProductGroupBuilder {
+	b.data.Videos = append(b.data.Videos, video.data)
+	return b
+}
+
+// Audio adds a new `product:audio` property.
+func (b *ProductGroupBuilder) Audio(audio *AudioBuilder) *ProductGroupBuilder {
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *ProductGroupBuilder) Facebook(facebook *FacebookBuilder) *ProductGroupBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *ProductGroupBuilder) AppLink(appLink *AppLinkBuilder) *ProductGroupBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *ProductGroupBuilder) Restrictions(restrictions *RestrictionsBuilder) *ProductGroupBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *ProductGroupBuilder) TTL(ttl time.Duration) *ProductGroupBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *ProductGroupBuilder) UpdatedTime(updatedTime time.Time) *ProductGroupBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *ProductGroupBuilder) RichAttachment(richAttachment bool) *ProductGroupBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *ProductGroupBuilder) Property(ns, prop, content string) *ProductGroupBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *ProductGroupBuilder) Namespace(prefix, uri string) *ProductGroupBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *ProductGroupBuilder) Defaults(defaults *Defaults) *ProductGroupBuilder {
+	b.data.defaults = defaults
+	return b
+}
+
+// RetailerGroupID sets the `product:retailer_group_id` property, referenced
+// by the `product:item_group_id` property of the variants.
+func (b *ProductGroupBuilder) RetailerGroupID(id string) *ProductGroupBuilder {
+	b.data.RetailerGroupID = id
+	return b
+}
+
+// Brand sets the `product:brand` property.
+func (b *ProductGroupBuilder) Brand(brand string) *ProductGroupBuilder {
+	b.data.Brand = brand
+	return b
+}
+
+// Data returns the properties of the `product.group` object. Changes made
+// to the returned value are reflected in the builder.
+func (b *ProductGroupBuilder) Data() *ProductGroupData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `product.group` object.
+func (d ProductGroupData) Builder() *ProductGroupBuilder {
+	return &ProductGroupBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `product.group`.
+func (b *ProductGroupBuilder) Type() string {
+	return "product.group"
+}
+
+// Validate checks the `product.group` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *ProductGroupBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
+// HTML renders the `product.group` object to be used in HTML templates.
+func (b *ProductGroupBuilder) HTML() template.HTML {
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `product.group` object as a schema.org `ProductGroup` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *ProductGroupBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `product.group` object as HTML markup.
+func (b *ProductGroupBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `product.group` object, in rendering
+// order.
+func (b *ProductGroupBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `product.group` object.
+func (b *ProductGroupBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `product.group` object as HTML markup into w.
+func (b *ProductGroupBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
+}
+
+func (b *ProductGroupBuilder) meta() *metaBuilder {
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *ProductGroupData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "product.group")
+	if d.RetailerGroupID != "" {
+		mb.Add("product", "retailer_group_id", d.RetailerGroupID)
+	}
+	if d.Brand != "" {
+		mb.Add("product", "brand", d.Brand)
+	}
+}
+
+func (d *ProductGroupData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+}
+
+func (d *ProductGroupData) linkedData() linkedData {
+	ld := d.baseLinkedData("ProductGroup")
+	ld.set("productGroupID", d.RetailerGroupID)
+	if d.Brand != "" {
+		ld.set("brand", linkedData{"@type": "Brand", "name": d.Brand})
+	}
+	return ld
+}
+
+func (d *ProductGroupData) decode(g *group) {
+	switch g.Name {
+	case "product:retailer_group_id":
+		d.RetailerGroupID = g.Content
+	case "product:brand":
+		d.Brand = g.Content
+	default:
+		d.baseDecode(g, "og:")
+	}
+}
@@ -0,0 +1,136 @@
+package ogp_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestProduct(t *testing.T) {
+	start := time.Date(2020, 11, 27, 0, 0, 0, 0, time.UTC)
+	product := ogp.Product().
+		Title("Running Shoes").
+		URL("http://example.com/shoes/42").
+		Image(ogp.Image().URL("http://example.com/shoes/42.jpg")).
+		Price(ogp.NewDecimal(4990, 2), "EUR").
+		Availability(ogp.AvailabilityInStock).
+		Condition(ogp.ConditionNew).
+		RetailerItemID("SH-42").
+		Brand("Acme").
+		SalePrice(ogp.NewDecimal(39, 0), "EUR").
+		SaleDates(start, start.AddDate(0, 0, 4)).
+		ItemGroupID("SH")
+	expected := `<meta property="og:type" content="product.item">
+<meta property="og:title" content="Running Shoes">
+<meta property="og:url" content="http://example.com/shoes/42">
+<meta property="og:image" content="http://example.com/shoes/42.jpg">
+<meta property="product:price:amount" content="49.90">
+<meta property="product:price:currency" content="EUR">
+<meta property="product:availability" content="in stock">
+<meta property="product:condition" content="new">
+<meta property="product:retailer_item_id" content="SH-42">
+<meta property="product:brand" content="Acme">
+<meta property="product:sale_price:amount" content="39">
+<meta property="product:sale_price:currency" content="EUR">
+<meta property="product:sale_price_dates:start" content="2020-11-27T00:00:00Z">
+<meta property="product:sale_price_dates:end" content="2020-12-01T00:00:00Z">
+<meta property="product:item_group_id" content="SH">`
+	if result := product.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if err := product.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if prefix := ogp.Prefix(product); prefix != "og: https://ogp.me/ns# product: https://ogp.me/ns/product#" {
+		t.Errorf("unexpected prefix: %s", prefix)
+	}
+}
+
+func TestProductValidate(t *testing.T) {
+	start := time.Date(2020, 11, 27, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		product  *ogp.ProductBuilder
+		expected string
+	}{
+		{
+			product:  ogp.Product(),
+			expected: "ogp: product:price:amount is required",
+		},
+		{
+			product:  ogp.Product().Price(ogp.NewDecimal(-1, 0), "eur"),
+			expected: "ogp: product:price:amount must not be negative\nogp: product:price:currency must be an ISO 4217 currency code",
+		},
+		{
+			product:  ogp.Product().Price(ogp.NewDecimal(10, 0), "").SalePrice(ogp.NewDecimal(5, 0), "XYZ"),
+			expected: "ogp: product:price:currency is required\nogp: product:sale_price:currency must be an ISO 4217 currency code",
+		},
+		{
+			product: ogp.Product().Price(ogp.NewDecimal(10, 0), "USD").Availability("sold").Condition("broken").
+				SaleDates(start, start.AddDate(0, 0, -1)),
+			expected: "ogp: product:availability has an unknown value\nogp: product:condition has an unknown value\nogp: product:sale_price_dates:end has an unknown value",
+		},
+	}
+	for _, test := range tests {
+		test.product.Title("Shoes").URL("http://example.com/shoes").Image(ogp.Image().URL("http://example.com/shoes.jpg"))
+		if err := test.product.Validate(); err == nil || err.Error() != test.expected {
+			t.Errorf("unexpected error: %v, expected: %s", err, test.expected)
+		}
+	}
+}
+
+func TestProductGroup(t *testing.T) {
+	group := ogp.ProductGroup().Title("Running Shoes").URL("http://example.com/shoes").RetailerGroupID("SH").Brand("Acme")
+	expected := `<meta property="og:type" content="product.group">
+<meta property="og:title" content="Running Shoes">
+<meta property="og:url" content="http://example.com/shoes">
+<meta property="product:retailer_group_id" content="SH">
+<meta property="product:brand" content="Acme">`
+	if result := group.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0", "0"},
+		{"19.90", "19.90"},
+		{"-0.05", "-0.05"},
+		{"+7", "7"},
+		{".5", "0.5"},
+		{"1234567890.123456789", "1234567890.123456789"},
+	}
+	for _, test := range tests {
+		d, err := ogp.ParseDecimal(test.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.input, err)
+			continue
+		}
+		if result := d.String(); result != test.expected {
+			t.Errorf("%s: unexpected result: %s", test.input, result)
+		}
+	}
+	for _, input := range []string{"", "-", "1.2.3", "1e3", "--1", "12a", "99999999999999999999"} {
+		if _, err := ogp.ParseDecimal(input); err == nil {
+			t.Errorf("%s: expected an error", input)
+		}
+	}
+	if result := ogp.NewDecimal(5, 3).String(); result != "0.005" {
+		t.Errorf("unexpected result: %s", result)
+	}
+	if result := ogp.NewDecimal(5, -2).String(); result != "500" {
+		t.Errorf("unexpected result: %s", result)
+	}
+	data, err := json.Marshal(ogp.PriceData{Amount: ogp.MustParseDecimal("0.10"), Currency: "USD"})
+	if err != nil || string(data) != `{"amount":"0.10","currency":"USD"}` {
+		t.Errorf("unexpected JSON: %s, %v", data, err)
+	}
+	var price ogp.PriceData
+	if err := json.Unmarshal(data, &price); err != nil || price.Amount.String() != "0.10" {
+		t.Errorf("unexpected price: %v, %v", price, err)
+	}
+}
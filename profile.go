@@ -1,163 +1,297 @@
 package ogp
 
-import "html/template"
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// ProfileData holds the properties of a `profile` object.
+type ProfileData struct {
+	WebsiteData
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Gender    string `json:"gender,omitempty"`
+}
 
 // ProfileBuilder builds a `profile` object.
 type ProfileBuilder struct {
-	WebsiteBuilder
-	firstName string
-	lastName  string
-	username  string
-	gender    string
+	data ProfileData
 }
 
 // Title sets the `profile:title` property.
 func (b *ProfileBuilder) Title(title string) *ProfileBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `profile:url` property.
 func (b *ProfileBuilder) URL(url string) *ProfileBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `profile:description` property.
 func (b *ProfileBuilder) Description(description string) *ProfileBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `profile:determiner` property.
 func (b *ProfileBuilder) Determiner(determiner string) *ProfileBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `profile:locale` or adds a new `profile:locale:alternate` property.
 func (b *ProfileBuilder) Locale(locale string) *ProfileBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `profile:site_name` property.
 func (b *ProfileBuilder) SiteName(siteName string) *ProfileBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `profile:image` property.
 func (b *ProfileBuilder) Image(image *ImageBuilder) *ProfileBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `profile:video` property.
 func (b *ProfileBuilder) Video(video *VideoBuilder) *ProfileBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `profile:audio` property.
 func (b *ProfileBuilder) Audio(audio *AudioBuilder) *ProfileBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *ProfileBuilder) Facebook(facebook *FacebookBuilder) *ProfileBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *ProfileBuilder) AppLink(appLink *AppLinkBuilder) *ProfileBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *ProfileBuilder) Restrictions(restrictions *RestrictionsBuilder) *ProfileBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *ProfileBuilder) TTL(ttl time.Duration) *ProfileBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *ProfileBuilder) UpdatedTime(updatedTime time.Time) *ProfileBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *ProfileBuilder) RichAttachment(richAttachment bool) *ProfileBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *ProfileBuilder) Property(ns, prop, content string) *ProfileBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *ProfileBuilder) Namespace(prefix, uri string) *ProfileBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *ProfileBuilder) Defaults(defaults *Defaults) *ProfileBuilder {
+	b.data.defaults = defaults
 	return b
 }
 
 // FirstName sets the `profile:first_name` property.
 func (b *ProfileBuilder) FirstName(firstName string) *ProfileBuilder {
-	b.firstName = firstName
+	b.data.FirstName = firstName
 	return b
 }
 
 // LastName sets the `profile:last_name` property.
 func (b *ProfileBuilder) LastName(lastName string) *ProfileBuilder {
-	b.lastName = lastName
+	b.data.LastName = lastName
 	return b
 }
 
 // Username sets the `profile:username` property.
 func (b *ProfileBuilder) Username(username string) *ProfileBuilder {
-	b.username = username
+	b.data.Username = username
 	return b
 }
 
 // Gender sets the `profile:gender` property.
 func (b *ProfileBuilder) Gender(gender string) *ProfileBuilder {
-	b.gender = gender
+	b.data.Gender = gender
 	return b
 }
 
+// Data returns the properties of the `profile` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *ProfileBuilder) Data() *ProfileData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the `profile`
+// object.
+func (d ProfileData) Builder() *ProfileBuilder {
+	return &ProfileBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `profile`.
+func (b *ProfileBuilder) Type() string {
+	return "profile"
+}
+
+// Validate checks the `profile` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *ProfileBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v, "", "og")
+	return v.err()
+}
+
 // HTML renders the `profile` object to be used in HTML templates.
 func (b *ProfileBuilder) HTML() template.HTML {
-	return b.meta("og").HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `profile` object as a schema.org `Person` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *ProfileBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `profile` object as HTML markup.
+func (b *ProfileBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `profile` object, in rendering
+// order.
+func (b *ProfileBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `profile` object.
+func (b *ProfileBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `profile` object as HTML markup into w.
+func (b *ProfileBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
+}
+
+func (b *ProfileBuilder) meta() *metaBuilder {
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb, "og")
+	b.data.customMeta(mb)
+	return mb
 }
 
-func (b *ProfileBuilder) meta(ns string) *metaBuilder {
-	var mb metaBuilder
+func (d *ProfileData) meta(mb *metaBuilder, ns string) {
+	d.baseMeta(mb, ns, "profile")
+	pns := ns
 	if ns == "og" {
-		mb.Add(ns, "type", "profile")
-		mb.Add(ns, "title", b.title)
-		mb.Add(ns, "url", b.url)
-	} else {
-		mb.Add(ns, "", b.url)
-		if b.title != "" {
-			mb.Add(ns, "title", b.title)
-		}
-	}
-	if b.description != "" {
-		mb.Add(ns, "description", b.description)
+		pns = "profile"
 	}
-	if b.determiner != "" {
-		mb.Add(ns, "determiner", b.determiner)
+	if d.FirstName != "" {
+		mb.Add(pns, "first_name", d.FirstName)
 	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add(ns, "locale", locale)
-		} else {
-			mb.Add(ns, "locale:alternate", locale)
-		}
+	if d.LastName != "" {
+		mb.Add(pns, "last_name", d.LastName)
 	}
-	if b.siteName != "" {
-		mb.Add(ns, "site_name", b.siteName)
+	if d.Username != "" {
+		mb.Add(pns, "username", d.Username)
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta(ns))
+	if d.Gender != "" {
+		mb.Add(pns, "gender", d.Gender)
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta(ns))
-	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta(ns))
-	}
-	if b.firstName != "" {
-		if ns == "og" {
-			mb.Add("profile", "first_name", b.firstName)
-		} else {
-			mb.Add(ns, "first_name", b.firstName)
-		}
-	}
-	if b.lastName != "" {
-		if ns == "og" {
-			mb.Add("profile", "last_name", b.lastName)
-		} else {
-			mb.Add(ns, "last_name", b.lastName)
-		}
+}
+
+func (d *ProfileData) validate(v *validator, path, ns string) {
+	d.baseValidate(v, path, ns)
+}
+
+func (d *ProfileData) linkedData() linkedData {
+	ld := d.baseLinkedData("Person")
+	if d.Title == "" {
+		ld.set("name", strings.TrimSpace(d.FirstName+" "+d.LastName))
 	}
-	if b.username != "" {
-		if ns == "og" {
-			mb.Add("profile", "username", b.username)
-		} else {
-			mb.Add(ns, "username", b.username)
-		}
+	ld.set("givenName", d.FirstName)
+	ld.set("familyName", d.LastName)
+	ld.set("alternateName", d.Username)
+	ld.set("gender", d.Gender)
+	return ld
+}
+
+func (d *ProfileData) decode(g *group, og, ns string) {
+	switch g.Name {
+	case ns + "first_name":
+		d.FirstName = g.Content
+	case ns + "last_name":
+		d.LastName = g.Content
+	case ns + "username":
+		d.Username = g.Content
+	case ns + "gender":
+		d.Gender = g.Content
+	default:
+		d.baseDecode(g, og)
 	}
-	if b.gender != "" {
-		if ns == "og" {
-			mb.Add("profile", "gender", b.gender)
-		} else {
-			mb.Add(ns, "gender", b.gender)
-		}
+}
+
+// decodeProfile decodes a profile referenced by another object, such as
+// `article:author`.
+func decodeProfile(g *group) ProfileData {
+	d := ProfileData{WebsiteData: WebsiteData{URL: g.Content}}
+	for _, sg := range groupProperties(g.props, "image", "video", "audio") {
+		d.decode(sg, "", "")
 	}
-	return &mb
+	return d
 }
@@ -0,0 +1,128 @@
+package ogp
+
+import (
+	"net/url"
+	"strings"
+)
+
+// urlProperties lists the local names of the properties holding a URL,
+// either directly, e.g. `og:image:secure_url`, or as a reference to another
+// object, e.g. `article:author` or `music:song`.
+var urlProperties = map[string]bool{
+	"url":        true,
+	"secure_url": true,
+	"image":      true,
+	"video":      true,
+	"audio":      true,
+	"see_also":   true,
+	"author":     true,
+	"publisher":  true,
+	"song":       true,
+	"album":      true,
+	"musician":   true,
+	"creator":    true,
+	"actor":      true,
+	"director":   true,
+	"writer":     true,
+	"series":     true,
+	"website":    true,
+}
+
+// isURLProperty reports whether the property with the given name holds a
+// URL. The URLs of App Links are left alone, except for the web fallback,
+// since they target apps rather than documents.
+func isURLProperty(name string) bool {
+	index := strings.IndexByte(name, ':')
+	if index < 0 {
+		return false
+	}
+	switch ns := name[:index]; {
+	case ns == "al":
+		return name == "al:web:url"
+	case ns == "fb" || !containsString(namespaces, ns):
+		return false
+	}
+	return urlProperties[name[strings.LastIndexByte(name, ':')+1:]]
+}
+
+// resolveURL resolves s against base and normalizes the result. s is
+// returned as is if base is nil or s isn't a valid URL.
+func resolveURL(base *url.URL, s string) string {
+	if base == nil || s == "" {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	return normalizeURL(base.ResolveReference(u))
+}
+
+// normalizeURL lowercases the scheme and the host of u, and drops the
+// default port of its scheme.
+func normalizeURL(u *url.URL) string {
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Host != "" {
+		host, port := strings.ToLower(u.Hostname()), u.Port()
+		if strings.IndexByte(host, ':') >= 0 {
+			host = "[" + host + "]"
+		}
+		if port == "" || u.Scheme == "http" && port == "80" || u.Scheme == "https" && port == "443" {
+			u.Host = host
+		} else {
+			u.Host = host + ":" + port
+		}
+	}
+	return u.String()
+}
+
+// resolveProperties resolves the URLs held by props against base.
+func resolveProperties(props []Property, base *url.URL) {
+	if base == nil {
+		return
+	}
+	for i := range props {
+		if isURLProperty(props[i].Name) {
+			props[i].Content = resolveURL(base, props[i].Content)
+		}
+	}
+}
+
+// linkedDataURLs lists the schema.org properties holding URLs.
+var linkedDataURLs = map[string]bool{
+	"url":          true,
+	"image":        true,
+	"contentUrl":   true,
+	"embedUrl":     true,
+	"thumbnailUrl": true,
+}
+
+// resolveLinkedData resolves the URLs held by ld and its nested objects
+// against base.
+func resolveLinkedData(ld linkedData, base *url.URL) {
+	if base == nil {
+		return
+	}
+	for key, value := range ld {
+		switch v := value.(type) {
+		case string:
+			if linkedDataURLs[key] {
+				ld[key] = resolveURL(base, v)
+			}
+		case []string:
+			if linkedDataURLs[key] {
+				urls := make([]string, len(v))
+				for i := range v {
+					urls[i] = resolveURL(base, v[i])
+				}
+				ld[key] = urls
+			}
+		case linkedData:
+			resolveLinkedData(v, base)
+		case []linkedData:
+			for i := range v {
+				resolveLinkedData(v[i], base)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package ogp
+
+// Values of the `og:restrictions:age` property.
+const (
+	RestrictionAge13 = "13+"
+	RestrictionAge16 = "16+"
+	RestrictionAge17 = "17+"
+	RestrictionAge18 = "18+"
+	RestrictionAge19 = "19+"
+	RestrictionAge21 = "21+"
+)
+
+// Values of the `og:restrictions:content` property.
+const (
+	RestrictionAlcohol = "alcohol"
+)
+
+// RestrictionsData holds the `og:restrictions:*` properties, which restrict
+// the audience of an object by country, age or content.
+type RestrictionsData struct {
+	AllowedCountries    []string `json:"allowed_countries,omitempty"`
+	DisallowedCountries []string `json:"disallowed_countries,omitempty"`
+	Age                 string   `json:"age,omitempty"`
+	Content             []string `json:"content,omitempty"`
+}
+
+// RestrictionsBuilder builds the `og:restrictions:*` properties of an object.
+type RestrictionsBuilder struct {
+	data RestrictionsData
+}
+
+// AllowCountry adds a new `og:restrictions:country:allowed` property, the
+// ISO 3166-1 alpha-2 code of a country the object is restricted to.
+func (b *RestrictionsBuilder) AllowCountry(country string) *RestrictionsBuilder {
+	b.data.AllowedCountries = append(b.data.AllowedCountries, country)
+	return b
+}
+
+// DisallowCountry adds a new `og:restrictions:country:disallowed` property,
+// the ISO 3166-1 alpha-2 code of a country the object is not available in.
+func (b *RestrictionsBuilder) DisallowCountry(country string) *RestrictionsBuilder {
+	b.data.DisallowedCountries = append(b.data.DisallowedCountries, country)
+	return b
+}
+
+// Age sets the `og:restrictions:age` property, the minimum age of the
+// audience, e.g. RestrictionAge18.
+func (b *RestrictionsBuilder) Age(age string) *RestrictionsBuilder {
+	b.data.Age = age
+	return b
+}
+
+// Content adds a new `og:restrictions:content` property, e.g.
+// RestrictionAlcohol.
+func (b *RestrictionsBuilder) Content(content string) *RestrictionsBuilder {
+	b.data.Content = append(b.data.Content, content)
+	return b
+}
+
+// Data returns the `og:restrictions:*` properties. Changes made to the
+// returned value are reflected in the builder.
+func (b *RestrictionsBuilder) Data() *RestrictionsData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the `og:restrictions:*`
+// properties.
+func (d RestrictionsData) Builder() *RestrictionsBuilder {
+	return &RestrictionsBuilder{data: d}
+}
+
+func (d *RestrictionsData) meta(mb *metaBuilder) {
+	for _, country := range d.AllowedCountries {
+		mb.Add("og", "restrictions:country:allowed", country)
+	}
+	for _, country := range d.DisallowedCountries {
+		mb.Add("og", "restrictions:country:disallowed", country)
+	}
+	if d.Age != "" {
+		mb.Add("og", "restrictions:age", d.Age)
+	}
+	for _, content := range d.Content {
+		mb.Add("og", "restrictions:content", content)
+	}
+}
+
+// validate checks the restrictions. A country can't be both allowed and
+// disallowed, so the specification forbids mixing both lists.
+func (d *RestrictionsData) validate(v *validator, path string) {
+	for _, country := range d.AllowedCountries {
+		if !isCountry(country) {
+			v.add(path, "og:restrictions:country:allowed", RuleValue)
+		}
+	}
+	for _, country := range d.DisallowedCountries {
+		if !isCountry(country) {
+			v.add(path, "og:restrictions:country:disallowed", RuleValue)
+		}
+	}
+	if len(d.AllowedCountries) > 0 && len(d.DisallowedCountries) > 0 {
+		v.add(path, "og:restrictions:country:disallowed", RuleValue)
+	}
+	v.oneOf(path, "og:restrictions:age", d.Age,
+		RestrictionAge13, RestrictionAge16, RestrictionAge17, RestrictionAge18, RestrictionAge19, RestrictionAge21)
+	for _, content := range d.Content {
+		v.oneOf(path, "og:restrictions:content", content, RestrictionAlcohol)
+	}
+}
+
+// decode decodes a single `og:restrictions:*` property and reports whether it
+// is known.
+func (d *RestrictionsData) decode(g *group) bool {
+	switch g.Name {
+	case "og:restrictions:country:allowed":
+		d.AllowedCountries = append(d.AllowedCountries, g.Content)
+	case "og:restrictions:country:disallowed":
+		d.DisallowedCountries = append(d.DisallowedCountries, g.Content)
+	case "og:restrictions:age":
+		d.Age = g.Content
+	case "og:restrictions:content":
+		d.Content = append(d.Content, g.Content)
+	default:
+		return false
+	}
+	return true
+}
+
+// isCountry reports whether s is shaped like an ISO 3166-1 alpha-2 code.
+func isCountry(s string) bool {
+	return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+}
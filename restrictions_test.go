@@ -0,0 +1,80 @@
+package ogp_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestRestrictions(t *testing.T) {
+	updated := time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)
+	movie := ogp.Movie().
+		Title("Movie").
+		URL("http://example.com/movie").
+		Image(ogp.Image().URL("http://example.com/movie.jpg")).
+		UpdatedTime(updated).
+		TTL(96*time.Hour + 1500*time.Millisecond).
+		RichAttachment(true).
+		Restrictions(ogp.Restrictions().
+			AllowCountry("US").
+			AllowCountry("CA").
+			Age(ogp.RestrictionAge21).
+			Content(ogp.RestrictionAlcohol)).
+		Duration(2 * time.Hour)
+	expected := `<meta property="og:type" content="video.movie">
+<meta property="og:title" content="Movie">
+<meta property="og:url" content="http://example.com/movie">
+<meta property="og:image" content="http://example.com/movie.jpg">
+<meta property="og:updated_time" content="2020-05-01T10:30:00Z">
+<meta property="og:ttl" content="345602">
+<meta property="og:rich_attachment" content="true">
+<meta property="og:restrictions:country:allowed" content="US">
+<meta property="og:restrictions:country:allowed" content="CA">
+<meta property="og:restrictions:age" content="21+">
+<meta property="og:restrictions:content" content="alcohol">
+<meta property="video:duration" content="7200">`
+	if result := movie.String(); result != expected {
+		t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, expected)
+	}
+	if err := movie.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	object, err := ogp.Parse(strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := object.(*ogp.VideoMovieBuilder).Data()
+	if !reflect.DeepEqual(data.Restrictions, movie.Data().Restrictions) {
+		t.Errorf("unexpected restrictions: %+v", data.Restrictions)
+	}
+	if data.TTL != 345602*time.Second || !data.RichAttachment || data.UpdatedTime == nil || !data.UpdatedTime.Equal(updated) {
+		t.Errorf("unexpected properties: %+v", data.WebsiteData)
+	}
+	if len(data.Custom) != 0 {
+		t.Errorf("unexpected custom properties: %v", data.Custom)
+	}
+}
+
+func TestRestrictionsValidate(t *testing.T) {
+	website := ogp.Website().
+		Title("Example").
+		URL("http://example.com").
+		Image(ogp.Image().URL("http://example.com/logo.png")).
+		TTL(-time.Second).
+		Restrictions(ogp.Restrictions().
+			AllowCountry("us").
+			DisallowCountry("FR").
+			Age("12+").
+			Content("tobacco"))
+	expected := `ogp: og:ttl must not be negative
+ogp: og:restrictions:country:allowed has an unknown value
+ogp: og:restrictions:country:disallowed has an unknown value
+ogp: og:restrictions:age has an unknown value
+ogp: og:restrictions:content has an unknown value`
+	if err := website.Validate(); err == nil || err.Error() != expected {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package ogp
+
+import (
+	"bufio"
+	"bytes"
+	"html"
+	"io"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	textToken tokenType = iota
+	startTagToken
+	endTagToken
+)
+
+type attribute struct {
+	name  string
+	value string
+}
+
+type token struct {
+	typ   tokenType
+	name  string
+	attrs []attribute
+	text  string
+}
+
+func (t *token) attr(name string) (string, bool) {
+	for _, attr := range t.attrs {
+		if attr.name == name {
+			return attr.value, true
+		}
+	}
+	return "", false
+}
+
+// tokenizer is a small, forgiving HTML tokenizer. It only understands what is
+// needed to find metadata: tags with their attributes, text, comments and the
+// raw text elements whose content must not be interpreted as markup.
+type tokenizer struct {
+	r   *bufio.Reader
+	raw string
+	end string
+}
+
+func newTokenizer(r io.Reader) *tokenizer {
+	return &tokenizer{r: bufio.NewReader(r)}
+}
+
+// rawText lists the elements whose content is not markup. The value tells
+// whether character references are decoded in their content.
+var rawText = map[string]bool{
+	"script":    false,
+	"style":     false,
+	"xmp":       false,
+	"iframe":    false,
+	"noembed":   false,
+	"noframes":  false,
+	"title":     true,
+	"textarea":  true,
+	"plaintext": false,
+}
+
+// next returns the next token, or io.EOF once the input is exhausted.
+func (z *tokenizer) next() (token, error) {
+	if z.end != "" {
+		t := token{typ: endTagToken, name: z.end}
+		z.end = ""
+		return t, nil
+	}
+	if z.raw != "" {
+		return z.readRawText()
+	}
+	// Comments, doctypes, processing instructions and empty end tags are
+	// skipped in a loop, since a document may hold any number of them.
+	for {
+		c, err := z.r.ReadByte()
+		if err != nil {
+			return token{}, err
+		}
+		if c != '<' {
+			z.r.UnreadByte()
+			return z.readText()
+		}
+		c, err = z.r.ReadByte()
+		if err != nil {
+			return token{typ: textToken, text: "<"}, nil
+		}
+		switch {
+		case c == '!':
+			z.skipMarkupDeclaration()
+			continue
+		case c == '?':
+			z.skipUntil(">")
+			continue
+		case c == '/':
+			if name := z.readEndTag(); name != "" {
+				return token{typ: endTagToken, name: name}, nil
+			}
+			continue
+		case isLetter(c):
+			z.r.UnreadByte()
+			return z.readStartTag()
+		}
+		z.r.UnreadByte()
+		text, err := z.readText()
+		text.text = "<" + text.text
+		return text, err
+	}
+}
+
+func (z *tokenizer) readText() (token, error) {
+	s, err := z.r.ReadString('<')
+	if err == nil {
+		z.r.UnreadByte()
+		s = s[:len(s)-1]
+	} else if err != io.EOF {
+		return token{}, err
+	}
+	return token{typ: textToken, text: html.UnescapeString(s)}, nil
+}
+
+func (z *tokenizer) readRawText() (token, error) {
+	name := z.raw
+	z.raw = ""
+	var buf bytes.Buffer
+	closing := "</" + name
+	for {
+		s, err := z.r.ReadString('<')
+		buf.WriteString(s)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return token{}, err
+		}
+		if name == "plaintext" {
+			continue
+		}
+		peek, _ := z.r.Peek(len(closing) - 1)
+		if strings.EqualFold(string(peek), closing[1:]) {
+			next, _ := z.r.Peek(len(closing))
+			if len(next) == len(closing)-1 || isSpace(next[len(next)-1]) || next[len(next)-1] == '>' || next[len(next)-1] == '/' {
+				buf.Truncate(buf.Len() - 1)
+				z.skipUntil(">")
+				z.end = name
+				break
+			}
+		}
+	}
+	text := buf.String()
+	if rawText[name] {
+		text = html.UnescapeString(text)
+	}
+	return token{typ: textToken, text: text}, nil
+}
+
+func (z *tokenizer) skipMarkupDeclaration() {
+	peek, _ := z.r.Peek(2)
+	if string(peek) == "--" {
+		z.r.Discard(2)
+		z.skipUntil("-->")
+	} else {
+		z.skipUntil(">")
+	}
+}
+
+// readEndTag reads an end tag and returns its name, empty for a tag to be
+// skipped.
+func (z *tokenizer) readEndTag() string {
+	name := z.readName()
+	z.skipUntil(">")
+	return name
+}
+
+func (z *tokenizer) readStartTag() (token, error) {
+	t := token{typ: startTagToken, name: z.readName()}
+	for {
+		c, err := z.r.ReadByte()
+		if err != nil {
+			return t, nil
+		}
+		switch {
+		case c == '>':
+			if _, ok := rawText[t.name]; ok {
+				z.raw = t.name
+			}
+			return t, nil
+		case isSpace(c) || c == '/':
+			continue
+		}
+		z.r.UnreadByte()
+		attr := attribute{name: z.readAttributeName()}
+		if z.skipSpaces() == '=' {
+			z.r.ReadByte()
+			z.skipSpaces()
+			attr.value = html.UnescapeString(z.readAttributeValue())
+		}
+		t.attrs = append(t.attrs, attr)
+	}
+}
+
+func (z *tokenizer) readName() string {
+	var buf bytes.Buffer
+	for {
+		c, err := z.r.ReadByte()
+		if err != nil {
+			break
+		}
+		if isSpace(c) || c == '/' || c == '>' {
+			z.r.UnreadByte()
+			break
+		}
+		buf.WriteByte(c)
+	}
+	return strings.ToLower(buf.String())
+}
+
+func (z *tokenizer) readAttributeName() string {
+	var buf bytes.Buffer
+	for {
+		c, err := z.r.ReadByte()
+		if err != nil {
+			break
+		}
+		if isSpace(c) || c == '/' || c == '>' || (c == '=' && buf.Len() > 0) {
+			z.r.UnreadByte()
+			break
+		}
+		buf.WriteByte(c)
+	}
+	return strings.ToLower(buf.String())
+}
+
+func (z *tokenizer) readAttributeValue() string {
+	c, err := z.r.ReadByte()
+	if err != nil {
+		return ""
+	}
+	if c == '"' || c == '\'' {
+		s, err := z.r.ReadString(c)
+		if err == nil {
+			s = s[:len(s)-1]
+		}
+		return s
+	}
+	z.r.UnreadByte()
+	var buf bytes.Buffer
+	for {
+		c, err := z.r.ReadByte()
+		if err != nil {
+			break
+		}
+		if isSpace(c) || c == '>' {
+			z.r.UnreadByte()
+			break
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// skipSpaces discards white spaces and returns the next byte without
+// consuming it.
+func (z *tokenizer) skipSpaces() byte {
+	for {
+		c, err := z.r.ReadByte()
+		if err != nil {
+			return 0
+		}
+		if !isSpace(c) {
+			z.r.UnreadByte()
+			return c
+		}
+	}
+}
+
+func (z *tokenizer) skipUntil(delim string) {
+	last := delim[len(delim)-1]
+	var window []byte
+	for {
+		c, err := z.r.ReadByte()
+		if err != nil {
+			return
+		}
+		window = append(window, c)
+		if len(window) > len(delim) {
+			window = window[1:]
+		}
+		if c == last && bytes.HasSuffix(window, []byte(delim)) {
+			return
+		}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
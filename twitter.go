@@ -0,0 +1,303 @@
+package ogp
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Twitter Card types.
+const (
+	CardSummary           = "summary"
+	CardSummaryLargeImage = "summary_large_image"
+	CardPlayer            = "player"
+	CardApp               = "app"
+)
+
+// Smallest image accepted by `summary_large_image` cards.
+const (
+	largeImageMinWidth  = 300
+	largeImageMinHeight = 157
+)
+
+// TwitterCardBuilder builds the Twitter Card of an Open Graph object. The
+// card is derived from the properties of the object, and Twitter falls back
+// to the `og:*` properties for its title, description and image, so only
+// the tags that Twitter can't derive by itself get rendered.
+type TwitterCardBuilder struct {
+	object      Object
+	card        string
+	site        string
+	creator     string
+	title       string
+	description string
+	image       string
+	imageAlt    string
+	apps        []twitterApp
+	defaults    *Defaults
+}
+
+type twitterApp struct {
+	platform string
+	id       string
+	url      string
+	name     string
+}
+
+// Card sets the `twitter:card` property. By default, the type of card is
+// `app` when apps are given, `player` for objects with an HTTPS video,
+// `summary_large_image` for objects with a large enough image, or `summary`
+// otherwise.
+func (b *TwitterCardBuilder) Card(card string) *TwitterCardBuilder {
+	b.card = card
+	return b
+}
+
+// Site sets the `twitter:site` property, the @username of the website.
+func (b *TwitterCardBuilder) Site(site string) *TwitterCardBuilder {
+	b.site = site
+	return b
+}
+
+// Creator sets the `twitter:creator` property, the @username of the content
+// creator.
+func (b *TwitterCardBuilder) Creator(creator string) *TwitterCardBuilder {
+	b.creator = creator
+	return b
+}
+
+// Title overrides the `og:title` property with the `twitter:title` one.
+func (b *TwitterCardBuilder) Title(title string) *TwitterCardBuilder {
+	b.title = title
+	return b
+}
+
+// Description overrides the `og:description` property with the
+// `twitter:description` one.
+func (b *TwitterCardBuilder) Description(description string) *TwitterCardBuilder {
+	b.description = description
+	return b
+}
+
+// Image overrides the first `og:image` property with the `twitter:image` one.
+func (b *TwitterCardBuilder) Image(url string) *TwitterCardBuilder {
+	b.image = url
+	return b
+}
+
+// ImageAlt overrides the first `og:image:alt` property with the
+// `twitter:image:alt` one.
+func (b *TwitterCardBuilder) ImageAlt(alt string) *TwitterCardBuilder {
+	b.imageAlt = alt
+	return b
+}
+
+// IPhoneApp adds the `twitter:app:*:iphone` properties of an `app` card.
+func (b *TwitterCardBuilder) IPhoneApp(id, url, name string) *TwitterCardBuilder {
+	b.apps = append(b.apps, twitterApp{platform: "iphone", id: id, url: url, name: name})
+	return b
+}
+
+// IPadApp adds the `twitter:app:*:ipad` properties of an `app` card.
+func (b *TwitterCardBuilder) IPadApp(id, url, name string) *TwitterCardBuilder {
+	b.apps = append(b.apps, twitterApp{platform: "ipad", id: id, url: url, name: name})
+	return b
+}
+
+// GooglePlayApp adds the `twitter:app:*:googleplay` properties of an `app`
+// card.
+func (b *TwitterCardBuilder) GooglePlayApp(id, url, name string) *TwitterCardBuilder {
+	b.apps = append(b.apps, twitterApp{platform: "googleplay", id: id, url: url, name: name})
+	return b
+}
+
+// Defaults sets the site-wide properties of the card. The `twitter:site` and
+// `twitter:creator` properties default to their TwitterSite and
+// TwitterCreator handles.
+func (b *TwitterCardBuilder) Defaults(defaults *Defaults) *TwitterCardBuilder {
+	b.defaults = defaults
+	return b
+}
+
+// HTML renders the Twitter Card to be used in HTML templates.
+func (b *TwitterCardBuilder) HTML() template.HTML {
+	return template.HTML(b.String())
+}
+
+// String renders the Twitter Card as HTML markup.
+func (b *TwitterCardBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the Twitter Card, in rendering order.
+func (b *TwitterCardBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// WriteTo renders the Twitter Card as HTML markup into w.
+func (b *TwitterCardBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
+}
+
+// twitterSource holds the properties of an object that a card is derived
+// from.
+type twitterSource struct {
+	title       string
+	description string
+	image       string
+	imageAlt    string
+	imageWidth  int
+	imageHeight int
+	video       string
+	videoSecure string
+	videoWidth  int
+	videoHeight int
+}
+
+func newTwitterSource(props []Property) *twitterSource {
+	var s twitterSource
+	var images, videos int
+	for _, prop := range props {
+		switch prop.Name {
+		case "og:title":
+			s.title = prop.Content
+		case "og:description":
+			s.description = prop.Content
+		case "og:image", "og:image:url":
+			images++
+			if images == 1 {
+				s.image = prop.Content
+			}
+		case "og:video", "og:video:url":
+			videos++
+			if videos == 1 {
+				s.video = prop.Content
+			}
+		}
+		if images == 1 {
+			switch prop.Name {
+			case "og:image:alt":
+				s.imageAlt = prop.Content
+			case "og:image:width":
+				s.imageWidth, _ = strconv.Atoi(prop.Content)
+			case "og:image:height":
+				s.imageHeight, _ = strconv.Atoi(prop.Content)
+			}
+		}
+		if videos == 1 {
+			switch prop.Name {
+			case "og:video:secure_url":
+				s.videoSecure = prop.Content
+			case "og:video:width":
+				s.videoWidth, _ = strconv.Atoi(prop.Content)
+			case "og:video:height":
+				s.videoHeight, _ = strconv.Atoi(prop.Content)
+			}
+		}
+	}
+	return &s
+}
+
+// player returns the URL of the video to be played in a `player` card, which
+// must be served over HTTPS.
+func (s *twitterSource) player() string {
+	if s.videoSecure != "" {
+		return s.videoSecure
+	}
+	if strings.HasPrefix(s.video, "https://") {
+		return s.video
+	}
+	return ""
+}
+
+func (s *twitterSource) largeImage() bool {
+	if s.image == "" {
+		return false
+	}
+	if s.imageWidth == 0 && s.imageHeight == 0 {
+		return true
+	}
+	return s.imageWidth >= largeImageMinWidth && s.imageHeight >= largeImageMinHeight
+}
+
+func (b *TwitterCardBuilder) meta() *metaBuilder {
+	mb := newMetaBuilder("name")
+	s := newTwitterSource(b.object.Properties())
+	card := b.card
+	if card == "" {
+		switch {
+		case len(b.apps) > 0:
+			card = CardApp
+		case s.player() != "":
+			card = CardPlayer
+		case s.largeImage():
+			card = CardSummaryLargeImage
+		default:
+			card = CardSummary
+		}
+	}
+	mb.Add("twitter", "card", card)
+	site, creator := b.site, b.creator
+	if b.defaults != nil {
+		if site == "" {
+			site = b.defaults.TwitterSite
+		}
+		if creator == "" {
+			creator = b.defaults.TwitterCreator
+		}
+	}
+	if site != "" {
+		mb.Add("twitter", "site", site)
+	}
+	if creator != "" {
+		mb.Add("twitter", "creator", creator)
+	}
+	if b.title != "" && b.title != s.title {
+		mb.Add("twitter", "title", b.title)
+	}
+	if b.description != "" && b.description != s.description {
+		mb.Add("twitter", "description", b.description)
+	}
+	image := b.image
+	if b.defaults != nil {
+		image = resolveURL(b.defaults.Base, image)
+	}
+	if image != "" && image != s.image {
+		mb.Add("twitter", "image", image)
+	}
+	if b.imageAlt != "" {
+		mb.Add("twitter", "image:alt", b.imageAlt)
+	} else if s.imageAlt != "" && (image == "" || image == s.image) {
+		mb.Add("twitter", "image:alt", s.imageAlt)
+	}
+	if card == CardPlayer && s.player() != "" {
+		mb.Add("twitter", "player", s.player())
+		if s.videoWidth > 0 {
+			mb.AddInt("twitter", "player:width", s.videoWidth)
+		}
+		if s.videoHeight > 0 {
+			mb.AddInt("twitter", "player:height", s.videoHeight)
+		}
+	}
+	if card == CardApp {
+		for _, app := range b.apps {
+			if app.id != "" {
+				mb.Add("twitter", "app:id:"+app.platform, app.id)
+			}
+			if app.url != "" {
+				mb.Add("twitter", "app:url:"+app.platform, app.url)
+			}
+			if app.name != "" {
+				mb.Add("twitter", "app:name:"+app.platform, app.name)
+			}
+		}
+	}
+	return mb
+}
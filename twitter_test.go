@@ -0,0 +1,62 @@
+package ogp_test
+
+import (
+	"testing"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestTwitterCard(t *testing.T) {
+	website := func() *ogp.WebsiteBuilder {
+		return ogp.Website().Title("Example").URL("http://example.com").Description("An example")
+	}
+	tests := []struct {
+		card     *ogp.TwitterCardBuilder
+		expected string
+	}{
+		{
+			card:     ogp.TwitterCard(website()),
+			expected: `<meta name="twitter:card" content="summary">`,
+		},
+		{
+			card: ogp.TwitterCard(website().Image(ogp.Image().URL("http://example.com/logo.png").Width(64).Height(64))).
+				Title("Example").Description("Another example").Image("http://example.com/logo.png"),
+			expected: `<meta name="twitter:card" content="summary">
+<meta name="twitter:description" content="Another example">`,
+		},
+		{
+			card: ogp.TwitterCard(website().Image(ogp.Image().URL("http://example.com/banner.png").Alt("Banner"))).
+				Creator("@jsmith"),
+			expected: `<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:creator" content="@jsmith">
+<meta name="twitter:image:alt" content="Banner">`,
+		},
+		{
+			card: ogp.TwitterCard(website().Image(ogp.Image().URL("http://example.com/banner.png").Alt("Banner"))).
+				Image("http://example.com/twitter.png"),
+			expected: `<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="http://example.com/twitter.png">`,
+		},
+		{
+			card: ogp.TwitterCard(website().Video(ogp.Video().URL("http://example.com/video").SecureURL("https://example.com/video"))),
+			expected: `<meta name="twitter:card" content="player">
+<meta name="twitter:player" content="https://example.com/video">`,
+		},
+		{
+			card:     ogp.TwitterCard(website().Video(ogp.Video().URL("http://example.com/video"))),
+			expected: `<meta name="twitter:card" content="summary">`,
+		},
+		{
+			card: ogp.TwitterCard(website()).IPhoneApp("307234931", "example://home", "Example"),
+			expected: `<meta name="twitter:card" content="app">
+<meta name="twitter:app:id:iphone" content="307234931">
+<meta name="twitter:app:url:iphone" content="example://home">
+<meta name="twitter:app:name:iphone" content="Example">`,
+		},
+	}
+	for _, test := range tests {
+		if result := test.card.String(); result != test.expected {
+			t.Errorf("unexpected result:\n%s\nexpected:\n%s", result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package ogp
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is a rule of the specification that a property can violate.
+type Rule string
+
+// Rules checked by Validate.
+const (
+	// RuleRequired is violated by a missing required property.
+	RuleRequired Rule = "required"
+	// RuleURL is violated by a property that is not an absolute URL.
+	RuleURL Rule = "url"
+	// RuleNonNegative is violated by a negative number.
+	RuleNonNegative Rule = "non-negative"
+	// RulePositive is violated by a duration that isn't at least one second
+	// once rounded to whole seconds.
+	RulePositive Rule = "positive"
+	// RuleRange is violated by a number out of the range allowed by the
+	// specification, e.g. a latitude greater than 90 degrees.
+	RuleRange Rule = "range"
+	// RuleCurrency is violated by a currency that is not an ISO 4217 code.
+	RuleCurrency Rule = "currency"
+	// RuleValue is violated by a property whose value is not one of the
+	// values allowed by the specification.
+	RuleValue Rule = "value"
+	// RuleConsistency is violated by a reference that disagrees with the
+	// back-reference of the referenced object, e.g. a song listed by an
+	// album at another track than the one the song says.
+	RuleConsistency Rule = "consistency"
+	// RuleNamespace is violated by a custom property whose namespace is
+	// neither known nor registered.
+	RuleNamespace Rule = "namespace"
+)
+
+var ruleMessages = map[Rule]string{
+	RuleRequired:    "is required",
+	RuleURL:         "must be an absolute URL",
+	RuleNonNegative: "must not be negative",
+	RulePositive:    "must be positive",
+	RuleRange:       "is out of range",
+	RuleCurrency:    "must be an ISO 4217 currency code",
+	RuleValue:       "has an unknown value",
+	RuleConsistency: "disagrees with the referenced object",
+	RuleNamespace:   "has an undeclared namespace",
+}
+
+// ValidationError reports a property that violates a rule of the
+// specification.
+type ValidationError struct {
+	// Path locates the nested object holding the property, e.g.
+	// `article:author[1]`, or is empty for the root object. Deeper objects
+	// are separated by slashes, e.g. `video:actor[0]/video:actor:image[2]`.
+	Path string
+	// Property is the name of the property, e.g. `og:title`.
+	Property string
+	// Rule is the violated rule.
+	Rule Rule
+}
+
+func (e *ValidationError) Error() string {
+	message, ok := ruleMessages[e.Rule]
+	if !ok {
+		message = "violates rule " + string(e.Rule)
+	}
+	if e.Path == "" {
+		return "ogp: " + e.Property + " " + message
+	}
+	return "ogp: " + e.Path + ": " + e.Property + " " + message
+}
+
+// ValidationErrors is the list of errors returned by Validate.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for index, err := range e {
+		messages[index] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+type validator struct {
+	errs ValidationErrors
+	// base is the URL the relative URLs are resolved against, if any.
+	base *url.URL
+}
+
+func (v *validator) add(path, prop string, rule Rule) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Property: prop, Rule: rule})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func (v *validator) required(path, prop, value string) bool {
+	if value == "" {
+		v.add(path, prop, RuleRequired)
+		return false
+	}
+	return true
+}
+
+func (v *validator) url(path, prop, value string) {
+	if value == "" {
+		return
+	}
+	value = resolveURL(v.base, value)
+	if u, err := url.Parse(value); err != nil || !u.IsAbs() || u.Host == "" {
+		v.add(path, prop, RuleURL)
+	}
+}
+
+func (v *validator) nonNegative(path, prop string, value int) {
+	if value < 0 {
+		v.add(path, prop, RuleNonNegative)
+	}
+}
+
+func (v *validator) positive(path, prop string, value *time.Duration) {
+	if value != nil && seconds(*value) <= 0 {
+		v.add(path, prop, RulePositive)
+	}
+}
+
+func (v *validator) inRange(path, prop string, value, min, max float64) {
+	if !(value >= min && value <= max) {
+		v.add(path, prop, RuleRange)
+	}
+}
+
+func (v *validator) currency(path, prop, value string) {
+	if value != "" && !isCurrency(value) {
+		v.add(path, prop, RuleCurrency)
+	}
+}
+
+func (v *validator) oneOf(path, prop, value string, values ...string) {
+	if value != "" && !containsString(values, value) {
+		v.add(path, prop, RuleValue)
+	}
+}
+
+// join returns the path of the index-th object referenced by prop.
+func join(path, prop string, index int) string {
+	elem := prop + "[" + strconv.Itoa(index) + "]"
+	if path == "" {
+		return elem
+	}
+	return path + "/" + elem
+}
+
+// name returns the name of the prop property of an object rendered under ns.
+func name(ns, prop string) string {
+	if prop == "" {
+		return ns
+	}
+	return ns + ":" + prop
+}
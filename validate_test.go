@@ -0,0 +1,114 @@
+package ogp_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/ogp.v1"
+)
+
+func TestValidate(t *testing.T) {
+	image := ogp.Image().URL("http://example.com/image.jpg")
+	tests := []struct {
+		object   ogp.Object
+		expected ogp.ValidationErrors
+	}{
+		{
+			object: ogp.Website().Title("Example").URL("http://example.com").Image(image),
+		},
+		{
+			object: ogp.Website(),
+			expected: ogp.ValidationErrors{
+				{Property: "og:title", Rule: ogp.RuleRequired},
+				{Property: "og:url", Rule: ogp.RuleRequired},
+				{Property: "og:image", Rule: ogp.RuleRequired},
+			},
+		},
+		{
+			object: ogp.Article().Title("Article").URL("/article").Image(ogp.Image().Width(-1)).
+				Author(ogp.Profile().URL("http://example.com/alice")).
+				Author(ogp.Profile().FirstName("Bob").Image(ogp.Image().URL("bob.jpg"))),
+			expected: ogp.ValidationErrors{
+				{Property: "og:url", Rule: ogp.RuleURL},
+				{Path: "og:image[0]", Property: "og:image", Rule: ogp.RuleRequired},
+				{Path: "og:image[0]", Property: "og:image:width", Rule: ogp.RuleNonNegative},
+				{Path: "article:author[1]", Property: "article:author", Rule: ogp.RuleRequired},
+				{Path: "article:author[1]/article:author:image[0]", Property: "article:author:image", Rule: ogp.RuleURL},
+			},
+		},
+		{
+			object: ogp.Album().Title("Album").URL("http://example.com/album").Image(image).
+				Song("http://example.com/song/1", 1, 1).Song("", -1, 2),
+			expected: ogp.ValidationErrors{
+				{Path: "music:song[1]", Property: "music:song", Rule: ogp.RuleRequired},
+				{Path: "music:song[1]", Property: "music:song:disc", Rule: ogp.RuleNonNegative},
+			},
+		},
+		{
+			object: ogp.Episode().Title("Episode").URL("http://example.com/episode").Image(image).
+				Actor(ogp.Profile(), "Hero").Series(ogp.TVShow().Title("Show")),
+			expected: ogp.ValidationErrors{
+				{Path: "video:actor[0]", Property: "video:actor", Rule: ogp.RuleRequired},
+				{Path: "video:series[0]", Property: "video:series", Rule: ogp.RuleRequired},
+			},
+		},
+		{
+			object: ogp.Movie().Title("Movie").URL("http://example.com/movie").Image(image).Duration(7200),
+			expected: ogp.ValidationErrors{
+				{Property: "video:duration", Rule: ogp.RulePositive},
+			},
+		},
+		{
+			object: ogp.Episode().Title("Episode").URL("http://example.com/episode").Image(image).Duration(-time.Minute).
+				Series(ogp.TVShow().URL("http://example.com/show").Duration(0)),
+			expected: ogp.ValidationErrors{
+				{Property: "video:duration", Rule: ogp.RulePositive},
+				{Path: "video:series[0]", Property: "video:series:duration", Rule: ogp.RulePositive},
+			},
+		},
+	}
+	for _, test := range tests {
+		err := test.object.Validate()
+		if test.expected == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.object.Type(), err)
+			}
+			continue
+		}
+		errs, ok := err.(ogp.ValidationErrors)
+		if !ok {
+			t.Errorf("%s: unexpected error: %v", test.object.Type(), err)
+			continue
+		}
+		if !reflect.DeepEqual(errs, test.expected) {
+			t.Errorf("%s: unexpected errors:\n%v\nexpected:\n%v", test.object.Type(), errs, test.expected)
+		}
+	}
+}
+
+func TestDurationRounding(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{3 * time.Minute, "180"},
+		{1499 * time.Millisecond, "1"},
+		{1500 * time.Millisecond, "2"},
+		{2*time.Minute + 29*time.Second + 500*time.Millisecond, "150"},
+		{499 * time.Millisecond, ""},
+		{7200, ""},
+		{-time.Minute, ""},
+	}
+	for _, test := range tests {
+		result := ""
+		for _, prop := range ogp.Song().URL("http://example.com/song").Duration(test.duration).Properties() {
+			if prop.Name == "music:duration" {
+				result = prop.Content
+			}
+		}
+		if result != test.expected {
+			t.Errorf("%v: unexpected duration: %q, expected: %q", test.duration, result, test.expected)
+		}
+	}
+}
@@ -2,168 +2,347 @@ package ogp
 
 import (
 	"html/template"
+	"io"
 	"time"
 )
 
+// VideoEpisodeData holds the properties of a `video.episode` object.
+type VideoEpisodeData struct {
+	WebsiteData
+	Duration    *time.Duration   `json:"duration,omitempty"`
+	ReleaseDate *DateTime        `json:"release_date,omitempty"`
+	Tags        []string         `json:"tags,omitempty"`
+	Actors      []VideoActorData `json:"actors,omitempty"`
+	Directors   []ProfileData    `json:"directors,omitempty"`
+	Writers     []ProfileData    `json:"writers,omitempty"`
+	Series      *VideoTVShowData `json:"series,omitempty"`
+}
+
 // VideoEpisodeBuilder builds a `video.episode` object.
 type VideoEpisodeBuilder struct {
-	WebsiteBuilder
-	duration    int
-	tags        []string
-	releaseDate *time.Time
-	actors      metaBuilder
-	directors   metaBuilder
-	writers     metaBuilder
-	series      metaBuilder
+	data VideoEpisodeData
 }
 
 // Title sets the `video:title` property.
 func (b *VideoEpisodeBuilder) Title(title string) *VideoEpisodeBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `video:url` property.
 func (b *VideoEpisodeBuilder) URL(url string) *VideoEpisodeBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `video:description` property.
 func (b *VideoEpisodeBuilder) Description(description string) *VideoEpisodeBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `video:determiner` property.
 func (b *VideoEpisodeBuilder) Determiner(determiner string) *VideoEpisodeBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `video:locale` or adds a new `video:locale:alternate` property.
 func (b *VideoEpisodeBuilder) Locale(locale string) *VideoEpisodeBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `video:site_name` property.
 func (b *VideoEpisodeBuilder) SiteName(siteName string) *VideoEpisodeBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `video:image` property.
 func (b *VideoEpisodeBuilder) Image(image *ImageBuilder) *VideoEpisodeBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `video:video` property.
 func (b *VideoEpisodeBuilder) Video(video *VideoBuilder) *VideoEpisodeBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `video:audio` property.
 func (b *VideoEpisodeBuilder) Audio(audio *AudioBuilder) *VideoEpisodeBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *VideoEpisodeBuilder) Facebook(facebook *FacebookBuilder) *VideoEpisodeBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *VideoEpisodeBuilder) AppLink(appLink *AppLinkBuilder) *VideoEpisodeBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *VideoEpisodeBuilder) Restrictions(restrictions *RestrictionsBuilder) *VideoEpisodeBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *VideoEpisodeBuilder) TTL(ttl time.Duration) *VideoEpisodeBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *VideoEpisodeBuilder) UpdatedTime(updatedTime time.Time) *VideoEpisodeBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *VideoEpisodeBuilder) RichAttachment(richAttachment bool) *VideoEpisodeBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *VideoEpisodeBuilder) Property(ns, prop, content string) *VideoEpisodeBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *VideoEpisodeBuilder) Namespace(prefix, uri string) *VideoEpisodeBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *VideoEpisodeBuilder) Defaults(defaults *Defaults) *VideoEpisodeBuilder {
+	b.data.defaults = defaults
 	return b
 }
 
-// Duration sets the `video:duration` property.
-func (b *VideoEpisodeBuilder) Duration(duration int) *VideoEpisodeBuilder {
-	b.duration = duration
+// Duration sets the `video:duration` property. It is rendered in whole
+// seconds, rounded to the nearest second with halves rounded away from zero.
+// A duration that rounds to zero or less, e.g. Duration(7200) rather than
+// Duration(2 * time.Hour), isn't rendered and is rejected by Validate.
+func (b *VideoEpisodeBuilder) Duration(duration time.Duration) *VideoEpisodeBuilder {
+	b.data.Duration = &duration
 	return b
 }
 
-// ReleaseDate sets the `video:release_date` property.
-func (b *VideoEpisodeBuilder) ReleaseDate(releaseDate time.Time) *VideoEpisodeBuilder {
-	b.releaseDate = &releaseDate
+// ReleaseDate sets the `video:release_date` property, either a
+// calendar date, e.g. NewDate(2020, time.May, 1), or an instant, e.g.
+// TimeOf(time.Now()).
+func (b *VideoEpisodeBuilder) ReleaseDate(releaseDate DateTime) *VideoEpisodeBuilder {
+	b.data.ReleaseDate = &releaseDate
 	return b
 }
 
 // Tag adds a new `video:tag` property.
 func (b *VideoEpisodeBuilder) Tag(tag string) *VideoEpisodeBuilder {
-	b.tags = append(b.tags, tag)
+	b.data.Tags = append(b.data.Tags, tag)
 	return b
 }
 
 // Actor adds a new `video:actor` property.
-func (b *VideoEpisodeBuilder) Actor(actor *ProfileBuilder, role string) *VideoEpisodeBuilder {
-	var mb metaBuilder
-	mb.Include(actor.meta("video:actor"))
-	if role != "" {
-		mb.Add("video:actor", "role", role)
-	}
-	b.actors.Include(&mb)
+func (b *VideoEpisodeBuilder) Actor(actor Object, role string) *VideoEpisodeBuilder {
+	b.data.Actors = append(b.data.Actors, VideoActorData{ProfileData: profileOf(actor), Role: role})
 	return b
 }
 
 // Director adds a new `video:director` property.
-func (b *VideoEpisodeBuilder) Director(director *ProfileBuilder) *VideoEpisodeBuilder {
-	b.directors.Include(director.meta("video:director"))
+func (b *VideoEpisodeBuilder) Director(director Object) *VideoEpisodeBuilder {
+	b.data.Directors = append(b.data.Directors, profileOf(director))
 	return b
 }
 
 // Writer adds a new `video:writer` property.
-func (b *VideoEpisodeBuilder) Writer(writer *ProfileBuilder) *VideoEpisodeBuilder {
-	b.writers.Include(writer.meta("video:writer"))
+func (b *VideoEpisodeBuilder) Writer(writer Object) *VideoEpisodeBuilder {
+	b.data.Writers = append(b.data.Writers, profileOf(writer))
 	return b
 }
 
 // Series adds a new `video:series` property.
-func (b *VideoEpisodeBuilder) Series(series *VideoTVShowBuilder) *VideoEpisodeBuilder {
-	b.writers.Include(series.meta("video:series"))
+func (b *VideoEpisodeBuilder) Series(series Object) *VideoEpisodeBuilder {
+	data := tvShowOf(series)
+	b.data.Series = &data
 	return b
 }
 
+// Data returns the properties of the `video.episode` object. Changes made to
+// the returned value are reflected in the builder.
+func (b *VideoEpisodeBuilder) Data() *VideoEpisodeData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `video.episode` object.
+func (d VideoEpisodeData) Builder() *VideoEpisodeBuilder {
+	return &VideoEpisodeBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `video.episode`.
+func (b *VideoEpisodeBuilder) Type() string {
+	return "video.episode"
+}
+
+// Validate checks the `video.episode` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *VideoEpisodeBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
 // HTML renders the `video.episode` object to be used in HTML templates.
 func (b *VideoEpisodeBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `video.episode` object as a schema.org `TVEpisode` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *VideoEpisodeBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `video.episode` object as HTML markup.
+func (b *VideoEpisodeBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `video.episode` object, in rendering
+// order.
+func (b *VideoEpisodeBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `video.episode` object.
+func (b *VideoEpisodeBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `video.episode` object as HTML markup into w.
+func (b *VideoEpisodeBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *VideoEpisodeBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "video.episode")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *VideoEpisodeData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "video.episode")
+	if d.Duration != nil {
+		mb.AddDuration("video", "duration", *d.Duration)
 	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
+	if d.ReleaseDate != nil {
+		mb.Add("video", "release_date", d.ReleaseDate.String())
 	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add("og", "locale", locale)
-		} else {
-			mb.Add("og", "locale:alternate", locale)
-		}
+	for _, tag := range d.Tags {
+		mb.Add("video", "tag", tag)
 	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
+	for i := range d.Actors {
+		d.Actors[i].meta(mb, "video:actor")
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+	for i := range d.Directors {
+		d.Directors[i].meta(mb, "video:director")
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+	for i := range d.Writers {
+		d.Writers[i].meta(mb, "video:writer")
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+	if d.Series != nil {
+		d.Series.meta(mb, "video:series")
 	}
-	if b.duration > 0 {
-		mb.Add("video", "duration", b.duration)
+}
+
+func (d *VideoEpisodeData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	v.positive("", "video:duration", d.Duration)
+	for i := range d.Actors {
+		d.Actors[i].validate(v, join("", "video:actor", i), "video:actor")
 	}
-	if b.releaseDate != nil {
-		mb.Add("video", "release_date", b.releaseDate.Format(time.RFC3339))
+	for i := range d.Directors {
+		d.Directors[i].validate(v, join("", "video:director", i), "video:director")
 	}
-	for _, tag := range b.tags {
-		mb.Add("video", "tag", tag)
+	for i := range d.Writers {
+		d.Writers[i].validate(v, join("", "video:writer", i), "video:writer")
+	}
+	if d.Series != nil {
+		d.Series.validate(v, join("", "video:series", 0), "video:series")
+	}
+}
+
+func (d *VideoEpisodeData) linkedData() linkedData {
+	ld := d.baseLinkedData("TVEpisode")
+	ld.set("duration", isoDuration(d.Duration))
+	ld.set("datePublished", d.ReleaseDate)
+	ld.set("keywords", d.Tags)
+	var actors []linkedData
+	for i := range d.Actors {
+		actor := linkedData{"@type": "PerformanceRole", "actor": d.Actors[i].linkedData()}
+		actor.set("characterName", d.Actors[i].Role)
+		actors = append(actors, actor)
+	}
+	ld.set("actor", actors)
+	ld.set("director", peopleLinkedData(d.Directors))
+	ld.set("author", peopleLinkedData(d.Writers))
+	if d.Series != nil {
+		ld.set("partOfSeries", d.Series.linkedData())
+	}
+	return ld
+}
+
+func (d *VideoEpisodeData) decode(g *group) {
+	switch g.Name {
+	case "video:duration":
+		d.Duration = parseDuration(g.Content)
+	case "video:release_date":
+		if t, err := ParseDateTime(g.Content); err == nil {
+			d.ReleaseDate = &t
+		}
+	case "video:tag":
+		d.Tags = append(d.Tags, g.Content)
+	case "video:actor":
+		d.Actors = append(d.Actors, VideoActorData{ProfileData: decodeProfile(g), Role: g.prop("role")})
+	case "video:director":
+		d.Directors = append(d.Directors, decodeProfile(g))
+	case "video:writer":
+		d.Writers = append(d.Writers, decodeProfile(g))
+	case "video:series":
+		series := decodeTVShow(g)
+		d.Series = &series
+	default:
+		d.baseDecode(g, "og:")
 	}
-	mb.Include(&b.actors)
-	mb.Include(&b.directors)
-	mb.Include(&b.writers)
-	return &mb
 }
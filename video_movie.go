@@ -2,161 +2,340 @@ package ogp
 
 import (
 	"html/template"
+	"io"
 	"time"
 )
 
+// VideoMovieData holds the properties of a `video.movie` object.
+type VideoMovieData struct {
+	WebsiteData
+	Duration    *time.Duration   `json:"duration,omitempty"`
+	ReleaseDate *DateTime        `json:"release_date,omitempty"`
+	Tags        []string         `json:"tags,omitempty"`
+	Actors      []VideoActorData `json:"actors,omitempty"`
+	Directors   []ProfileData    `json:"directors,omitempty"`
+	Writers     []ProfileData    `json:"writers,omitempty"`
+}
+
 // VideoMovieBuilder builds a `video.movie` object.
 type VideoMovieBuilder struct {
-	WebsiteBuilder
-	duration    int
-	tags        []string
-	releaseDate *time.Time
-	actors      metaBuilder
-	directors   metaBuilder
-	writers     metaBuilder
+	data VideoMovieData
 }
 
 // Title sets the `video:title` property.
 func (b *VideoMovieBuilder) Title(title string) *VideoMovieBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `video:url` property.
 func (b *VideoMovieBuilder) URL(url string) *VideoMovieBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `video:description` property.
 func (b *VideoMovieBuilder) Description(description string) *VideoMovieBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `video:determiner` property.
 func (b *VideoMovieBuilder) Determiner(determiner string) *VideoMovieBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `video:locale` or adds a new `video:locale:alternate` property.
 func (b *VideoMovieBuilder) Locale(locale string) *VideoMovieBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `video:site_name` property.
 func (b *VideoMovieBuilder) SiteName(siteName string) *VideoMovieBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `video:image` property.
 func (b *VideoMovieBuilder) Image(image *ImageBuilder) *VideoMovieBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `video:video` property.
 func (b *VideoMovieBuilder) Video(video *VideoBuilder) *VideoMovieBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `video:audio` property.
 func (b *VideoMovieBuilder) Audio(audio *AudioBuilder) *VideoMovieBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *VideoMovieBuilder) Facebook(facebook *FacebookBuilder) *VideoMovieBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *VideoMovieBuilder) AppLink(appLink *AppLinkBuilder) *VideoMovieBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *VideoMovieBuilder) Restrictions(restrictions *RestrictionsBuilder) *VideoMovieBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *VideoMovieBuilder) TTL(ttl time.Duration) *VideoMovieBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *VideoMovieBuilder) UpdatedTime(updatedTime time.Time) *VideoMovieBuilder {
+	b.data.UpdatedTime = &updatedTime
 	return b
 }
 
-// Duration sets the `video:duration` property.
-func (b *VideoMovieBuilder) Duration(duration int) *VideoMovieBuilder {
-	b.duration = duration
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *VideoMovieBuilder) RichAttachment(richAttachment bool) *VideoMovieBuilder {
+	b.data.RichAttachment = richAttachment
 	return b
 }
 
-// ReleaseDate sets the `video:release_date` property.
-func (b *VideoMovieBuilder) ReleaseDate(releaseDate time.Time) *VideoMovieBuilder {
-	b.releaseDate = &releaseDate
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *VideoMovieBuilder) Property(ns, prop, content string) *VideoMovieBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *VideoMovieBuilder) Namespace(prefix, uri string) *VideoMovieBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *VideoMovieBuilder) Defaults(defaults *Defaults) *VideoMovieBuilder {
+	b.data.defaults = defaults
+	return b
+}
+
+// Duration sets the `video:duration` property. It is rendered in whole
+// seconds, rounded to the nearest second with halves rounded away from zero.
+// A duration that rounds to zero or less, e.g. Duration(7200) rather than
+// Duration(2 * time.Hour), isn't rendered and is rejected by Validate.
+func (b *VideoMovieBuilder) Duration(duration time.Duration) *VideoMovieBuilder {
+	b.data.Duration = &duration
+	return b
+}
+
+// ReleaseDate sets the `video:release_date` property, either a
+// calendar date, e.g. NewDate(2020, time.May, 1), or an instant, e.g.
+// TimeOf(time.Now()).
+func (b *VideoMovieBuilder) ReleaseDate(releaseDate DateTime) *VideoMovieBuilder {
+	b.data.ReleaseDate = &releaseDate
 	return b
 }
 
 // Tag adds a new `video:tag` property.
 func (b *VideoMovieBuilder) Tag(tag string) *VideoMovieBuilder {
-	b.tags = append(b.tags, tag)
+	b.data.Tags = append(b.data.Tags, tag)
 	return b
 }
 
 // Actor adds a new `video:actor` property.
-func (b *VideoMovieBuilder) Actor(actor *ProfileBuilder, role string) *VideoMovieBuilder {
-	var mb metaBuilder
-	mb.Include(actor.meta("video:actor"))
-	if role != "" {
-		mb.Add("video:actor", "role", role)
-	}
-	b.actors.Include(&mb)
+func (b *VideoMovieBuilder) Actor(actor Object, role string) *VideoMovieBuilder {
+	b.data.Actors = append(b.data.Actors, VideoActorData{ProfileData: profileOf(actor), Role: role})
 	return b
 }
 
 // Director adds a new `video:director` property.
-func (b *VideoMovieBuilder) Director(director *ProfileBuilder) *VideoMovieBuilder {
-	b.directors.Include(director.meta("video:director"))
+func (b *VideoMovieBuilder) Director(director Object) *VideoMovieBuilder {
+	b.data.Directors = append(b.data.Directors, profileOf(director))
 	return b
 }
 
 // Writer adds a new `video:writer` property.
-func (b *VideoMovieBuilder) Writer(writer *ProfileBuilder) *VideoMovieBuilder {
-	b.writers.Include(writer.meta("video:writer"))
+func (b *VideoMovieBuilder) Writer(writer Object) *VideoMovieBuilder {
+	b.data.Writers = append(b.data.Writers, profileOf(writer))
 	return b
 }
 
+// Data returns the properties of the `video.movie` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *VideoMovieBuilder) Data() *VideoMovieData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `video.movie` object.
+func (d VideoMovieData) Builder() *VideoMovieBuilder {
+	return &VideoMovieBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `video.movie`.
+func (b *VideoMovieBuilder) Type() string {
+	return "video.movie"
+}
+
+// Validate checks the `video.movie` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *VideoMovieBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
 // HTML renders the `video.movie` object to be used in HTML templates.
 func (b *VideoMovieBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `video.movie` object as a schema.org `Movie` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *VideoMovieBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `video.movie` object as HTML markup.
+func (b *VideoMovieBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `video.movie` object, in rendering
+// order.
+func (b *VideoMovieBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `video.movie` object.
+func (b *VideoMovieBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `video.movie` object as HTML markup into w.
+func (b *VideoMovieBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *VideoMovieBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "video.movie")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *VideoMovieData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "video.movie")
+	if d.Duration != nil {
+		mb.AddDuration("video", "duration", *d.Duration)
 	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
+	if d.ReleaseDate != nil {
+		mb.Add("video", "release_date", d.ReleaseDate.String())
 	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add("og", "locale", locale)
-		} else {
-			mb.Add("og", "locale:alternate", locale)
-		}
+	for _, tag := range d.Tags {
+		mb.Add("video", "tag", tag)
 	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
+	for i := range d.Actors {
+		d.Actors[i].meta(mb, "video:actor")
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+	for i := range d.Directors {
+		d.Directors[i].meta(mb, "video:director")
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+	for i := range d.Writers {
+		d.Writers[i].meta(mb, "video:writer")
+	}
+}
+
+func (d *VideoMovieData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+	v.positive("", "video:duration", d.Duration)
+	for i := range d.Actors {
+		d.Actors[i].validate(v, join("", "video:actor", i), "video:actor")
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+	for i := range d.Directors {
+		d.Directors[i].validate(v, join("", "video:director", i), "video:director")
 	}
-	if b.duration > 0 {
-		mb.Add("video", "duration", b.duration)
+	for i := range d.Writers {
+		d.Writers[i].validate(v, join("", "video:writer", i), "video:writer")
 	}
-	if b.releaseDate != nil {
-		mb.Add("video", "release_date", b.releaseDate.Format(time.RFC3339))
+}
+
+func (d *VideoMovieData) linkedData() linkedData {
+	ld := d.baseLinkedData("Movie")
+	ld.set("duration", isoDuration(d.Duration))
+	ld.set("datePublished", d.ReleaseDate)
+	ld.set("keywords", d.Tags)
+	var actors []linkedData
+	for i := range d.Actors {
+		actor := linkedData{"@type": "PerformanceRole", "actor": d.Actors[i].linkedData()}
+		actor.set("characterName", d.Actors[i].Role)
+		actors = append(actors, actor)
 	}
-	for _, tag := range b.tags {
-		mb.Add("video", "tag", tag)
+	ld.set("actor", actors)
+	ld.set("director", peopleLinkedData(d.Directors))
+	ld.set("author", peopleLinkedData(d.Writers))
+	return ld
+}
+
+func (d *VideoMovieData) decode(g *group) {
+	switch g.Name {
+	case "video:duration":
+		d.Duration = parseDuration(g.Content)
+	case "video:release_date":
+		if t, err := ParseDateTime(g.Content); err == nil {
+			d.ReleaseDate = &t
+		}
+	case "video:tag":
+		d.Tags = append(d.Tags, g.Content)
+	case "video:actor":
+		d.Actors = append(d.Actors, VideoActorData{ProfileData: decodeProfile(g), Role: g.prop("role")})
+	case "video:director":
+		d.Directors = append(d.Directors, decodeProfile(g))
+	case "video:writer":
+		d.Writers = append(d.Writers, decodeProfile(g))
+	default:
+		d.baseDecode(g, "og:")
+	}
+}
+
+// VideoActorData is a `video:actor` of a video object.
+type VideoActorData struct {
+	ProfileData
+	Role string `json:"role,omitempty"`
+}
+
+func (d *VideoActorData) meta(mb *metaBuilder, ns string) {
+	d.ProfileData.meta(mb, ns)
+	if d.Role != "" {
+		mb.Add(ns, "role", d.Role)
 	}
-	mb.Include(&b.actors)
-	mb.Include(&b.directors)
-	mb.Include(&b.writers)
-	return &mb
 }
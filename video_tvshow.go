@@ -2,170 +2,348 @@ package ogp
 
 import (
 	"html/template"
+	"io"
 	"time"
 )
 
+// VideoTVShowData holds the properties of a `video.tv_show` object.
+type VideoTVShowData struct {
+	WebsiteData
+	Duration    *time.Duration   `json:"duration,omitempty"`
+	ReleaseDate *DateTime        `json:"release_date,omitempty"`
+	Tags        []string         `json:"tags,omitempty"`
+	Actors      []VideoActorData `json:"actors,omitempty"`
+	Directors   []ProfileData    `json:"directors,omitempty"`
+	Writers     []ProfileData    `json:"writers,omitempty"`
+}
+
 // VideoTVShowBuilder builds a `video.tv_show` object.
 type VideoTVShowBuilder struct {
-	WebsiteBuilder
-	duration    int
-	tags        []string
-	releaseDate *time.Time
-	actors      metaBuilder
-	directors   metaBuilder
-	writers     metaBuilder
+	data VideoTVShowData
 }
 
 // Title sets the `video:title` property.
 func (b *VideoTVShowBuilder) Title(title string) *VideoTVShowBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `video:url` property.
 func (b *VideoTVShowBuilder) URL(url string) *VideoTVShowBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `video:description` property.
 func (b *VideoTVShowBuilder) Description(description string) *VideoTVShowBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `video:determiner` property.
 func (b *VideoTVShowBuilder) Determiner(determiner string) *VideoTVShowBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `video:locale` or adds a new `video:locale:alternate` property.
 func (b *VideoTVShowBuilder) Locale(locale string) *VideoTVShowBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `video:site_name` property.
 func (b *VideoTVShowBuilder) SiteName(siteName string) *VideoTVShowBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `video:image` property.
 func (b *VideoTVShowBuilder) Image(image *ImageBuilder) *VideoTVShowBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `video:video` property.
 func (b *VideoTVShowBuilder) Video(video *VideoBuilder) *VideoTVShowBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `video:audio` property.
 func (b *VideoTVShowBuilder) Audio(audio *AudioBuilder) *VideoTVShowBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *VideoTVShowBuilder) Facebook(facebook *FacebookBuilder) *VideoTVShowBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *VideoTVShowBuilder) AppLink(appLink *AppLinkBuilder) *VideoTVShowBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *VideoTVShowBuilder) Restrictions(restrictions *RestrictionsBuilder) *VideoTVShowBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *VideoTVShowBuilder) TTL(ttl time.Duration) *VideoTVShowBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *VideoTVShowBuilder) UpdatedTime(updatedTime time.Time) *VideoTVShowBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *VideoTVShowBuilder) RichAttachment(richAttachment bool) *VideoTVShowBuilder {
+	b.data.RichAttachment = richAttachment
+	return b
+}
+
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *VideoTVShowBuilder) Property(ns, prop, content string) *VideoTVShowBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *VideoTVShowBuilder) Namespace(prefix, uri string) *VideoTVShowBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *VideoTVShowBuilder) Defaults(defaults *Defaults) *VideoTVShowBuilder {
+	b.data.defaults = defaults
 	return b
 }
 
-// Duration sets the `video:duration` property.
-func (b *VideoTVShowBuilder) Duration(duration int) *VideoTVShowBuilder {
-	b.duration = duration
+// Duration sets the `video:duration` property. It is rendered in whole
+// seconds, rounded to the nearest second with halves rounded away from zero.
+// A duration that rounds to zero or less, e.g. Duration(7200) rather than
+// Duration(2 * time.Hour), isn't rendered and is rejected by Validate.
+func (b *VideoTVShowBuilder) Duration(duration time.Duration) *VideoTVShowBuilder {
+	b.data.Duration = &duration
 	return b
 }
 
-// ReleaseDate sets the `video:release_date` property.
-func (b *VideoTVShowBuilder) ReleaseDate(releaseDate time.Time) *VideoTVShowBuilder {
-	b.releaseDate = &releaseDate
+// ReleaseDate sets the `video:release_date` property, either a
+// calendar date, e.g. NewDate(2020, time.May, 1), or an instant, e.g.
+// TimeOf(time.Now()).
+func (b *VideoTVShowBuilder) ReleaseDate(releaseDate DateTime) *VideoTVShowBuilder {
+	b.data.ReleaseDate = &releaseDate
 	return b
 }
 
 // Tag adds a new `video:tag` property.
 func (b *VideoTVShowBuilder) Tag(tag string) *VideoTVShowBuilder {
-	b.tags = append(b.tags, tag)
+	b.data.Tags = append(b.data.Tags, tag)
 	return b
 }
 
 // Actor adds a new `video:actor` property.
-func (b *VideoTVShowBuilder) Actor(actor *ProfileBuilder, role string) *VideoTVShowBuilder {
-	var mb metaBuilder
-	mb.Include(actor.meta("video:actor"))
-	if role != "" {
-		mb.Add("video:actor", "role", role)
-	}
-	b.actors.Include(&mb)
+func (b *VideoTVShowBuilder) Actor(actor Object, role string) *VideoTVShowBuilder {
+	b.data.Actors = append(b.data.Actors, VideoActorData{ProfileData: profileOf(actor), Role: role})
 	return b
 }
 
 // Director adds a new `video:director` property.
-func (b *VideoTVShowBuilder) Director(director *ProfileBuilder) *VideoTVShowBuilder {
-	b.directors.Include(director.meta("video:director"))
+func (b *VideoTVShowBuilder) Director(director Object) *VideoTVShowBuilder {
+	b.data.Directors = append(b.data.Directors, profileOf(director))
 	return b
 }
 
 // Writer adds a new `video:writer` property.
-func (b *VideoTVShowBuilder) Writer(writer *ProfileBuilder) *VideoTVShowBuilder {
-	b.writers.Include(writer.meta("video:writer"))
+func (b *VideoTVShowBuilder) Writer(writer Object) *VideoTVShowBuilder {
+	b.data.Writers = append(b.data.Writers, profileOf(writer))
 	return b
 }
 
+// Data returns the properties of the `video.tv_show` object. Changes made to
+// the returned value are reflected in the builder.
+func (b *VideoTVShowBuilder) Data() *VideoTVShowData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the
+// `video.tv_show` object.
+func (d VideoTVShowData) Builder() *VideoTVShowBuilder {
+	return &VideoTVShowBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `video.tv_show`.
+func (b *VideoTVShowBuilder) Type() string {
+	return "video.tv_show"
+}
+
+// Validate checks the `video.tv_show` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *VideoTVShowBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v, "", "og")
+	return v.err()
+}
+
 // HTML renders the `video.tv_show` object to be used in HTML templates.
 func (b *VideoTVShowBuilder) HTML() template.HTML {
-	return b.meta("og").HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `video.tv_show` object as a schema.org `TVSeries` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *VideoTVShowBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `video.tv_show` object as HTML markup.
+func (b *VideoTVShowBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `video.tv_show` object, in rendering
+// order.
+func (b *VideoTVShowBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `video.tv_show` object.
+func (b *VideoTVShowBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `video.tv_show` object as HTML markup into w.
+func (b *VideoTVShowBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
+}
+
+func (b *VideoTVShowBuilder) meta() *metaBuilder {
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb, "og")
+	b.data.customMeta(mb)
+	return mb
 }
 
-func (b *VideoTVShowBuilder) meta(ns string) *metaBuilder {
-	var mb metaBuilder
+func (d *VideoTVShowData) meta(mb *metaBuilder, ns string) {
+	d.baseMeta(mb, ns, "video.tv_show")
+	vns := ns
 	if ns == "og" {
-		mb.Add(ns, "type", "video.tv_show")
-		mb.Add(ns, "title", b.title)
-		mb.Add(ns, "url", b.url)
-	} else {
-		mb.Add(ns, "", b.url)
-		if b.title != "" {
-			mb.Add(ns, "title", b.title)
-		}
-	}
-	if b.description != "" {
-		mb.Add(ns, "description", b.description)
+		vns = "video"
 	}
-	if b.determiner != "" {
-		mb.Add(ns, "determiner", b.determiner)
-	}
-	for index, locale := range b.locales {
-		if index == 0 {
-			mb.Add(ns, "locale", locale)
-		} else {
-			mb.Add(ns, "locale:alternate", locale)
-		}
+	if d.Duration != nil {
+		mb.AddDuration(vns, "duration", *d.Duration)
 	}
-	if b.siteName != "" {
-		mb.Add(ns, "site_name", b.siteName)
+	if d.ReleaseDate != nil {
+		mb.Add(vns, "release_date", d.ReleaseDate.String())
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta(ns))
+	for _, tag := range d.Tags {
+		mb.Add(vns, "tag", tag)
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta(ns))
+	if ns == "og" {
+		for i := range d.Actors {
+			d.Actors[i].meta(mb, "video:actor")
+		}
+		for i := range d.Directors {
+			d.Directors[i].meta(mb, "video:director")
+		}
+		for i := range d.Writers {
+			d.Writers[i].meta(mb, "video:writer")
+		}
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta(ns))
+}
+
+func (d *VideoTVShowData) validate(v *validator, path, ns string) {
+	d.baseValidate(v, path, ns)
+	vns := ns
+	if ns == "og" {
+		vns = "video"
 	}
-	if b.duration > 0 {
-		mb.Add(ns, "duration", b.duration)
+	v.positive(path, vns+":duration", d.Duration)
+	if ns == "og" {
+		for i := range d.Actors {
+			d.Actors[i].validate(v, join(path, "video:actor", i), "video:actor")
+		}
+		for i := range d.Directors {
+			d.Directors[i].validate(v, join(path, "video:director", i), "video:director")
+		}
+		for i := range d.Writers {
+			d.Writers[i].validate(v, join(path, "video:writer", i), "video:writer")
+		}
 	}
-	if b.releaseDate != nil {
-		mb.Add(ns, "release_date", b.releaseDate.Format(time.RFC3339))
+}
+
+func (d *VideoTVShowData) linkedData() linkedData {
+	ld := d.baseLinkedData("TVSeries")
+	ld.set("duration", isoDuration(d.Duration))
+	ld.set("startDate", d.ReleaseDate)
+	ld.set("keywords", d.Tags)
+	var actors []linkedData
+	for i := range d.Actors {
+		actor := linkedData{"@type": "PerformanceRole", "actor": d.Actors[i].linkedData()}
+		actor.set("characterName", d.Actors[i].Role)
+		actors = append(actors, actor)
 	}
-	for _, tag := range b.tags {
-		mb.Add(ns, "tag", tag)
+	ld.set("actor", actors)
+	ld.set("director", peopleLinkedData(d.Directors))
+	ld.set("author", peopleLinkedData(d.Writers))
+	return ld
+}
+
+func (d *VideoTVShowData) decode(g *group, og, ns string) {
+	switch g.Name {
+	case ns + "duration":
+		d.Duration = parseDuration(g.Content)
+	case ns + "release_date":
+		if t, err := ParseDateTime(g.Content); err == nil {
+			d.ReleaseDate = &t
+		}
+	case ns + "tag":
+		d.Tags = append(d.Tags, g.Content)
+	case "video:actor":
+		d.Actors = append(d.Actors, VideoActorData{ProfileData: decodeProfile(g), Role: g.prop("role")})
+	case "video:director":
+		d.Directors = append(d.Directors, decodeProfile(g))
+	case "video:writer":
+		d.Writers = append(d.Writers, decodeProfile(g))
+	default:
+		d.baseDecode(g, og)
 	}
-	if ns != "og" {
-		mb.Include(&b.actors)
-		mb.Include(&b.directors)
-		mb.Include(&b.writers)
+}
+
+// decodeTVShow decodes a TV show referenced by an episode as `video:series`.
+func decodeTVShow(g *group) VideoTVShowData {
+	d := VideoTVShowData{WebsiteData: WebsiteData{URL: g.Content}}
+	for _, sg := range groupProperties(g.props, "image", "video", "audio") {
+		d.decode(sg, "", "")
 	}
-	return &mb
+	return d
 }
@@ -1,108 +1,453 @@
 package ogp
 
-import "html/template"
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebsiteData holds the properties of a `website` object. They are shared by
+// every other object type.
+type WebsiteData struct {
+	Title          string            `json:"title,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Determiner     string            `json:"determiner,omitempty"`
+	Locales        []string          `json:"locales,omitempty"`
+	SiteName       string            `json:"site_name,omitempty"`
+	Images         []ImageData       `json:"images,omitempty"`
+	Videos         []VideoData       `json:"videos,omitempty"`
+	Audios         []AudioData       `json:"audios,omitempty"`
+	SeeAlso        []string          `json:"see_also,omitempty"`
+	Facebook       *FacebookData     `json:"facebook,omitempty"`
+	AppLink        *AppLinkData      `json:"app_link,omitempty"`
+	Restrictions   *RestrictionsData `json:"restrictions,omitempty"`
+	TTL            time.Duration     `json:"ttl,omitempty"`
+	UpdatedTime    *time.Time        `json:"updated_time,omitempty"`
+	RichAttachment bool              `json:"rich_attachment,omitempty"`
+	Custom         []Property        `json:"custom,omitempty"`
+	Namespaces     []Namespace       `json:"namespaces,omitempty"`
+	// Sources tells where the properties filled in by a Parser with
+	// Fallback come from, by property name, e.g. `og:title`. The other
+	// properties come from the Open Graph properties of the document.
+	Sources map[string]Source `json:"sources,omitempty"`
+
+	// defaults are merged into the properties at render time.
+	defaults *Defaults
+}
 
 // WebsiteBuilder builds a `website` object.
 type WebsiteBuilder struct {
-	title       string
-	url         string
-	description string
-	determiner  string
-	locales     []string
-	siteName    string
-	images      []*ImageBuilder
-	videos      []*VideoBuilder
-	audios      []*AudioBuilder
+	data WebsiteData
 }
 
 // Title sets the `og:title` property.
 func (b *WebsiteBuilder) Title(title string) *WebsiteBuilder {
-	b.title = title
+	b.data.Title = title
 	return b
 }
 
 // URL sets the `og:url` property.
 func (b *WebsiteBuilder) URL(url string) *WebsiteBuilder {
-	b.url = url
+	b.data.URL = url
 	return b
 }
 
 // Description sets the `og:description` property.
 func (b *WebsiteBuilder) Description(description string) *WebsiteBuilder {
-	b.description = description
+	b.data.Description = description
 	return b
 }
 
 // Determiner sets the `og:determiner` property.
 func (b *WebsiteBuilder) Determiner(determiner string) *WebsiteBuilder {
-	b.determiner = determiner
+	b.data.Determiner = determiner
 	return b
 }
 
 // Locale sets the `og:locale` or adds a new `og:locale:alternate` property.
 func (b *WebsiteBuilder) Locale(locale string) *WebsiteBuilder {
-	b.locales = append(b.locales, locale)
+	b.data.Locales = append(b.data.Locales, locale)
 	return b
 }
 
 // SiteName sets the `og:site_name` property.
 func (b *WebsiteBuilder) SiteName(siteName string) *WebsiteBuilder {
-	b.siteName = siteName
+	b.data.SiteName = siteName
 	return b
 }
 
 // Image adds a new `og:image` property.
 func (b *WebsiteBuilder) Image(image *ImageBuilder) *WebsiteBuilder {
-	b.images = append(b.images, image)
+	b.data.Images = append(b.data.Images, image.data)
 	return b
 }
 
 // Video adds a new `og:video` property.
 func (b *WebsiteBuilder) Video(video *VideoBuilder) *WebsiteBuilder {
-	b.videos = append(b.videos, video)
+	b.data.Videos = append(b.data.Videos, video.data)
 	return b
 }
 
 // Audio adds a new `og:audio` property.
 func (b *WebsiteBuilder) Audio(audio *AudioBuilder) *WebsiteBuilder {
-	b.audios = append(b.audios, audio)
+	b.data.Audios = append(b.data.Audios, audio.data)
+	return b
+}
+
+// SeeAlso adds a new `og:see_also` property, the URL of a related page.
+func (b *WebsiteBuilder) SeeAlso(url string) *WebsiteBuilder {
+	b.data.SeeAlso = append(b.data.SeeAlso, url)
+	return b
+}
+
+// Facebook sets the `fb:app_id`, `fb:admins` and `fb:pages` properties.
+func (b *WebsiteBuilder) Facebook(facebook *FacebookBuilder) *WebsiteBuilder {
+	data := facebook.data
+	b.data.Facebook = &data
+	return b
+}
+
+// AppLink sets the `al:*` properties, which deep link the object into apps.
+func (b *WebsiteBuilder) AppLink(appLink *AppLinkBuilder) *WebsiteBuilder {
+	data := appLink.data
+	b.data.AppLink = &data
+	return b
+}
+
+// Restrictions sets the `og:restrictions:*` properties, which restrict the
+// audience of the object by country, age or content.
+func (b *WebsiteBuilder) Restrictions(restrictions *RestrictionsBuilder) *WebsiteBuilder {
+	data := restrictions.data
+	b.data.Restrictions = &data
+	return b
+}
+
+// TTL sets the `og:ttl` property, how long crawlers should wait before
+// scraping the object again. It is rendered in whole seconds, rounded like
+// durations.
+func (b *WebsiteBuilder) TTL(ttl time.Duration) *WebsiteBuilder {
+	b.data.TTL = ttl
+	return b
+}
+
+// UpdatedTime sets the `og:updated_time` property.
+func (b *WebsiteBuilder) UpdatedTime(updatedTime time.Time) *WebsiteBuilder {
+	b.data.UpdatedTime = &updatedTime
+	return b
+}
+
+// RichAttachment sets the `og:rich_attachment` property, which asks for the
+// object to be shared as a rich attachment.
+func (b *WebsiteBuilder) RichAttachment(richAttachment bool) *WebsiteBuilder {
+	b.data.RichAttachment = richAttachment
 	return b
 }
 
+// Property adds a custom property, rendered after the properties of the
+// object in the order they were added, e.g. Property("acme", "stock", "42")
+// for `acme:stock`. The namespace must be known or registered with Namespace.
+func (b *WebsiteBuilder) Property(ns, prop, content string) *WebsiteBuilder {
+	b.data.Custom = append(b.data.Custom, Property{Name: name(ns, prop), Content: content})
+	return b
+}
+
+// Namespace registers the URI of a namespace used by custom properties, to be
+// declared in the `prefix` attribute of the document.
+func (b *WebsiteBuilder) Namespace(prefix, uri string) *WebsiteBuilder {
+	b.data.Namespaces = append(b.data.Namespaces, Namespace{Prefix: prefix, URI: uri})
+	return b
+}
+
+// Defaults sets the site-wide properties merged into the object at render
+// time.
+func (b *WebsiteBuilder) Defaults(defaults *Defaults) *WebsiteBuilder {
+	b.data.defaults = defaults
+	return b
+}
+
+// Data returns the properties of the `website` object. Changes made to the
+// returned value are reflected in the builder.
+func (b *WebsiteBuilder) Data() *WebsiteData {
+	return &b.data
+}
+
+// Builder returns a builder initialized with the properties of the `website`
+// object.
+func (d WebsiteData) Builder() *WebsiteBuilder {
+	return &WebsiteBuilder{data: d}
+}
+
+// Type returns the `og:type` of the object, which is `website`.
+func (b *WebsiteBuilder) Type() string {
+	return "website"
+}
+
+// Validate checks the `website` object against the rules of the
+// specification. The returned error, if any, is a ValidationErrors.
+func (b *WebsiteBuilder) Validate() error {
+	v := validator{base: b.data.base()}
+	b.data.validate(&v)
+	return v.err()
+}
+
 // HTML renders the `website` object to be used in HTML templates.
 func (b *WebsiteBuilder) HTML() template.HTML {
-	return b.meta().HTML()
+	return template.HTML(b.String())
+}
+
+// JSONLD renders the `website` object as a schema.org `WebSite` in a JSON-LD
+// script element, to be used in HTML templates.
+func (b *WebsiteBuilder) JSONLD() template.HTML {
+	return renderLinkedData(b.data.linkedData(), b.data.base())
+}
+
+// String renders the `website` object as HTML markup.
+func (b *WebsiteBuilder) String() string {
+	mb := b.meta()
+	defer mb.release()
+	return mb.String()
+}
+
+// Properties returns the properties of the `website` object, in rendering
+// order.
+func (b *WebsiteBuilder) Properties() []Property {
+	mb := b.meta()
+	defer mb.release()
+	return mb.Properties()
+}
+
+// Namespaces returns the namespaces used by the `website` object.
+func (b *WebsiteBuilder) Namespaces() []Namespace {
+	return namespacesOf(b.Type(), b.Properties(), b.data.Namespaces)
+}
+
+// WriteTo renders the `website` object as HTML markup into w.
+func (b *WebsiteBuilder) WriteTo(w io.Writer) (int64, error) {
+	mb := b.meta()
+	defer mb.release()
+	return mb.WriteTo(w)
 }
 
 func (b *WebsiteBuilder) meta() *metaBuilder {
-	var mb metaBuilder
-	mb.Add("og", "type", "website")
-	mb.Add("og", "title", b.title)
-	mb.Add("og", "url", b.url)
-	if b.description != "" {
-		mb.Add("og", "description", b.description)
+	mb := newMetaBuilder("property")
+	mb.base = b.data.base()
+	b.data.meta(mb)
+	b.data.customMeta(mb)
+	return mb
+}
+
+func (d *WebsiteData) meta(mb *metaBuilder) {
+	d.baseMeta(mb, "og", "website")
+}
+
+// baseMeta adds the properties shared by every object type. The object is
+// rendered as a root object of the given type when ns is `og`, otherwise as
+// an object referenced by the ns property.
+func (d *WebsiteData) baseMeta(mb *metaBuilder, ns, typ string) {
+	if ns == "og" {
+		mb.Add(ns, "type", typ)
+		if d.Title != "" {
+			mb.Add(ns, "title", d.Title)
+		}
+		if d.URL != "" {
+			mb.Add(ns, "url", d.URL)
+		}
+	} else {
+		mb.Add(ns, "", d.URL)
+		if d.Title != "" {
+			mb.Add(ns, "title", d.Title)
+		}
+	}
+	if d.Description != "" {
+		mb.Add(ns, "description", d.Description)
 	}
-	if b.determiner != "" {
-		mb.Add("og", "determiner", b.determiner)
+	if d.Determiner != "" {
+		mb.Add(ns, "determiner", d.Determiner)
 	}
-	for index, locale := range b.locales {
+	for index, locale := range d.locales() {
 		if index == 0 {
-			mb.Add("og", "locale", locale)
+			mb.Add(ns, "locale", locale)
 		} else {
-			mb.Add("og", "locale:alternate", locale)
+			mb.Add(ns, "locale:alternate", locale)
+		}
+	}
+	if siteName := d.siteName(); siteName != "" {
+		mb.Add(ns, "site_name", siteName)
+	}
+	images := d.images()
+	for i := range images {
+		images[i].meta(mb, ns)
+	}
+	for i := range d.Videos {
+		d.Videos[i].meta(mb, ns)
+	}
+	for i := range d.Audios {
+		d.Audios[i].meta(mb, ns)
+	}
+	if ns == "og" {
+		for _, url := range d.SeeAlso {
+			mb.Add(ns, "see_also", url)
+		}
+		if d.UpdatedTime != nil {
+			mb.Add(ns, "updated_time", d.UpdatedTime.Format(time.RFC3339))
+		}
+		if d.TTL != 0 {
+			mb.AddDuration(ns, "ttl", d.TTL)
+		}
+		if d.RichAttachment {
+			mb.Add(ns, "rich_attachment", "true")
+		}
+		if d.Restrictions != nil {
+			d.Restrictions.meta(mb)
+		}
+		facebook := d.facebook()
+		facebook.meta(mb)
+		if d.AppLink != nil {
+			d.AppLink.meta(mb)
+		}
+	}
+}
+
+// customMeta adds the custom properties of a root object, after the ones
+// modeled by its type.
+func (d *WebsiteData) customMeta(mb *metaBuilder) {
+	for _, prop := range d.Custom {
+		mb.Add(prop.Name, "", prop.Content)
+	}
+}
+
+func (d *WebsiteData) validate(v *validator) {
+	d.baseValidate(v, "", "og")
+}
+
+// baseValidate checks the properties shared by every object type, rendered
+// under ns as baseMeta does.
+func (d *WebsiteData) baseValidate(v *validator, path, ns string) {
+	if ns == "og" {
+		v.required(path, "og:title", d.Title)
+		v.required(path, "og:url", d.URL)
+		if len(d.images()) == 0 {
+			v.add(path, "og:image", RuleRequired)
+		}
+		if d.TTL < 0 {
+			v.add(path, "og:ttl", RuleNonNegative)
+		}
+		if d.Restrictions != nil {
+			d.Restrictions.validate(v, path)
 		}
+		if d.AppLink != nil {
+			d.AppLink.validate(v, path)
+		}
+		for _, prop := range d.Custom {
+			if prefix := strings.SplitN(prop.Name, ":", 2)[0]; namespaceURI(prefix, d.Namespaces) == "" {
+				v.add(path, prop.Name, RuleNamespace)
+			}
+		}
+	} else {
+		v.required(path, ns, d.URL)
+	}
+	v.url(path, name(ns, "url"), d.URL)
+	images := d.images()
+	for i := range images {
+		images[i].validate(v, join(path, name(ns, "image"), i), ns)
 	}
-	if b.siteName != "" {
-		mb.Add("og", "site_name", b.siteName)
+	for _, url := range d.SeeAlso {
+		v.url(path, name(ns, "see_also"), url)
 	}
-	for _, image := range b.images {
-		mb.Include(image.meta("og"))
+
+	for i := range d.Videos {
+		d.Videos[i].validate(v, join(path, name(ns, "video"), i), ns)
 	}
-	for _, video := range b.videos {
-		mb.Include(video.meta("og"))
+	for i := range d.Audios {
+		d.Audios[i].validate(v, join(path, name(ns, "audio"), i), ns)
 	}
-	for _, audio := range b.audios {
-		mb.Include(audio.meta("og"))
+}
+
+func (d *WebsiteData) linkedData() linkedData {
+	return d.baseLinkedData("WebSite")
+}
+
+func (d *WebsiteData) decode(g *group) {
+	d.baseDecode(g, "og:")
+}
+
+// baseDecode decodes the properties shared by every object type. The
+// properties of a root object that no type models are kept as custom
+// properties.
+func (d *WebsiteData) baseDecode(g *group, ns string) {
+	switch g.Name {
+	case ns + "type":
+	case ns + "title":
+		d.Title = g.Content
+	case ns + "url":
+		d.URL = g.Content
+	case ns + "description":
+		d.Description = g.Content
+	case ns + "determiner":
+		d.Determiner = g.Content
+	case ns + "locale":
+		d.Locales = append([]string{g.Content}, d.Locales...)
+	case ns + "locale:alternate":
+		d.Locales = append(d.Locales, g.Content)
+	case ns + "site_name":
+		d.SiteName = g.Content
+	case ns + "image":
+		d.Images = append(d.Images, decodeImage(g))
+	case ns + "video":
+		d.Videos = append(d.Videos, decodeVideo(g))
+	case ns + "audio":
+		d.Audios = append(d.Audios, decodeAudio(g))
+	case ns + "see_also":
+		d.SeeAlso = append(d.SeeAlso, g.Content)
+	case "fb:app_id", "fb:admins", "fb:pages":
+		if d.Facebook == nil {
+			d.Facebook = &FacebookData{}
+		}
+		d.Facebook.decode(g)
+	default:
+		if ns != "og:" {
+			break
+		}
+		switch g.Name {
+		case "og:updated_time":
+			if t, ok := parseTime(g.Content); ok {
+				d.UpdatedTime = &t
+				return
+			}
+		case "og:ttl":
+			if seconds, err := strconv.ParseInt(g.Content, 10, 64); err == nil {
+				d.TTL = time.Duration(seconds) * time.Second
+				return
+			}
+		case "og:rich_attachment":
+			if richAttachment, err := strconv.ParseBool(g.Content); err == nil {
+				d.RichAttachment = richAttachment
+				return
+			}
+		}
+		if strings.HasPrefix(g.Name, "og:restrictions:") {
+			restrictions := d.Restrictions
+			if restrictions == nil {
+				restrictions = &RestrictionsData{}
+			}
+			if restrictions.decode(g) {
+				d.Restrictions = restrictions
+				break
+			}
+		}
+		if strings.HasPrefix(g.Name, "al:") {
+			appLink := d.AppLink
+			if appLink == nil {
+				appLink = &AppLinkData{}
+			}
+			if appLink.decode(g.Property) {
+				d.AppLink = appLink
+				break
+			}
+		}
+		d.Custom = append(d.Custom, g.Property)
 	}
-	return &mb
 }